@@ -0,0 +1,284 @@
+package gopherpanic
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type BreakerState uint
+
+const (
+	BreakerClosed   BreakerState = iota // The tasks run and their failures are counted
+	BreakerOpen                         // The tasks are rejected with CircuitOpenError
+	BreakerHalfOpen                     // A limited number of trial tasks run to probe the dependency
+)
+
+var breakerStateNames = map[BreakerState]string{
+	BreakerClosed:   "closed",
+	BreakerOpen:     "open",
+	BreakerHalfOpen: "half-open",
+}
+
+func (state BreakerState) String() string {
+	if name, exists := breakerStateNames[state]; exists {
+		return name
+	}
+
+	return fmt.Sprintf("state(%d)", uint(state))
+}
+
+// Configuration of a CircuitBreaker, the zero values use the defaults
+type CircuitBreakerOptions struct {
+	Name             string                                                // Name of the protected dependency, used in the errors and callbacks
+	TripKinds        []ErrorKind                                           // Kinds of the failures counted by the breaker (default: Network and Timeout)
+	FailureThreshold int                                                   // Consecutive failures which open the breaker (default: 5)
+	OpenTimeout      time.Duration                                         // Duration of the open state before the trial tasks (default: 30s)
+	HalfOpenRequests int                                                   // Successful trial tasks which close the breaker (default: 1)
+	Clock            func() time.Time                                      // Source of the current time (default: time.Now)
+	OnStateChange    func(name string, from BreakerState, to BreakerState) // Called after each state change, for metrics or logs
+}
+
+// Circuit breaker which stops calling a failing dependency.
+//
+// The failures are the errors whose cause chain contains a TripKinds code, the other errors count
+// as successes. The breaker opens after FailureThreshold consecutive failures, rejects the tasks
+// during OpenTimeout, then lets HalfOpenRequests trial tasks run: their success closes the breaker,
+// a failure opens it again.
+type CircuitBreaker struct {
+	options CircuitBreakerOptions
+
+	mutex      sync.Mutex
+	state      BreakerState
+	generation uint64
+	failures   int
+	successes  int
+	trials     int
+	openedAt   time.Time
+}
+
+type breakerTransition struct {
+	from BreakerState
+	to   BreakerState
+}
+
+// Create a closed CircuitBreaker
+func NewCircuitBreaker(options CircuitBreakerOptions) *CircuitBreaker {
+	if len(options.TripKinds) == 0 {
+		options.TripKinds = []ErrorKind{Network, Timeout}
+	}
+	if options.FailureThreshold <= 0 {
+		options.FailureThreshold = 5
+	}
+	if options.OpenTimeout <= 0 {
+		options.OpenTimeout = 30 * time.Second
+	}
+	if options.HalfOpenRequests <= 0 {
+		options.HalfOpenRequests = 1
+	}
+	if options.Clock == nil {
+		options.Clock = time.Now
+	}
+
+	return &CircuitBreaker{options: options}
+}
+
+// Current state, the open breaker becomes half-open when its OpenTimeout is elapsed
+func (breaker *CircuitBreaker) State() BreakerState {
+	breaker.mutex.Lock()
+	transitions := breaker.refresh(breaker.options.Clock())
+	state := breaker.state
+	breaker.mutex.Unlock()
+
+	breaker.notify(transitions)
+	return state
+}
+
+// Close the breaker and forget the counted failures
+func (breaker *CircuitBreaker) Reset() {
+	breaker.mutex.Lock()
+	transitions := breaker.transition(BreakerClosed, breaker.options.Clock())
+	breaker.mutex.Unlock()
+
+	breaker.notify(transitions)
+}
+
+// Run the task if the breaker allows it.
+//
+// Returns a CircuitOpenError spawned at the current position without running the task when the breaker
+// is open or when every trial task of the half-open breaker is running. The error is Retryable and its
+// RetryAfter is the remaining open duration.
+func (breaker *CircuitBreaker) Execute(ctx context.Context, task func(ctx context.Context) error) error {
+	generation, rejected := breaker.admit()
+	if rejected != nil {
+		rejected.Position = Position{}.spawn(2)
+		return rejected
+	}
+
+	panicking := true
+	defer func() {
+		if panicking {
+			breaker.report(generation, true)
+		}
+	}()
+
+	err := task(ctx)
+	panicking = false
+	breaker.report(generation, breaker.trips(err))
+	return err
+}
+
+// Admit a task, returns its generation or the rejection error
+func (breaker *CircuitBreaker) admit() (uint64, *Error) {
+	breaker.mutex.Lock()
+	now := breaker.options.Clock()
+	transitions := breaker.refresh(now)
+
+	var rejected *Error
+	switch breaker.state {
+	case BreakerOpen:
+		rejected = breaker.openError(breaker.openedAt.Add(breaker.options.OpenTimeout).Sub(now))
+	case BreakerHalfOpen:
+		if breaker.trials >= breaker.options.HalfOpenRequests {
+			rejected = breaker.openError(0)
+		} else {
+			breaker.trials++
+		}
+	}
+
+	generation := breaker.generation
+	breaker.mutex.Unlock()
+
+	breaker.notify(transitions)
+	return generation, rejected
+}
+
+// Count the result of a task admitted in the generation, the results of the previous states are ignored
+func (breaker *CircuitBreaker) report(generation uint64, failed bool) {
+	breaker.mutex.Lock()
+	now := breaker.options.Clock()
+
+	var transitions []breakerTransition
+	if generation == breaker.generation {
+		switch {
+		case breaker.state == BreakerHalfOpen && failed:
+			transitions = breaker.transition(BreakerOpen, now)
+		case breaker.state == BreakerHalfOpen:
+			breaker.successes++
+			if breaker.successes >= breaker.options.HalfOpenRequests {
+				transitions = breaker.transition(BreakerClosed, now)
+			}
+		case failed:
+			breaker.failures++
+			if breaker.failures >= breaker.options.FailureThreshold {
+				transitions = breaker.transition(BreakerOpen, now)
+			}
+		default:
+			breaker.failures = 0
+		}
+	}
+	breaker.mutex.Unlock()
+
+	breaker.notify(transitions)
+}
+
+// Move the open breaker to half-open when its OpenTimeout is elapsed, must be called with the mutex locked
+func (breaker *CircuitBreaker) refresh(now time.Time) []breakerTransition {
+	if breaker.state != BreakerOpen || now.Before(breaker.openedAt.Add(breaker.options.OpenTimeout)) {
+		return nil
+	}
+
+	return breaker.transition(BreakerHalfOpen, now)
+}
+
+// Change the state and reset the counters, must be called with the mutex locked
+func (breaker *CircuitBreaker) transition(to BreakerState, now time.Time) []breakerTransition {
+	from := breaker.state
+	breaker.state = to
+	breaker.generation++
+	breaker.failures = 0
+	breaker.successes = 0
+	breaker.trials = 0
+	if to == BreakerOpen {
+		breaker.openedAt = now
+	}
+
+	if from == to {
+		return nil
+	}
+
+	return []breakerTransition{{from: from, to: to}}
+}
+
+func (breaker *CircuitBreaker) notify(transitions []breakerTransition) {
+	if breaker.options.OnStateChange == nil {
+		return
+	}
+
+	for _, transition := range transitions {
+		breaker.options.OnStateChange(breaker.options.Name, transition.from, transition.to)
+	}
+}
+
+// The error is a failure of the dependency: its cause chain contains a code of TripKinds
+func (breaker *CircuitBreaker) trips(err error) bool {
+	if converted, isError := err.(*Error); err == nil || (isError && converted == nil) {
+		return false
+	}
+
+	for _, kind := range errorKinds(err) {
+		for _, tripKind := range breaker.options.TripKinds {
+			if kind == tripKind {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Kinds of the Errors of the cause chain, Unknown for the chains without Error
+func errorKinds(err error) []ErrorKind {
+	var kinds []ErrorKind
+	var walk func(err error)
+	walk = func(err error) {
+		switch converted := err.(type) {
+		case nil:
+			return
+		case *Error:
+			if converted == nil {
+				return
+			}
+			kinds = append(kinds, converted.Code.ID)
+		}
+
+		switch wrapper := err.(type) {
+		case interface{ Unwrap() error }:
+			walk(wrapper.Unwrap())
+		case interface{ Unwrap() []error }:
+			for _, cause := range wrapper.Unwrap() {
+				walk(cause)
+			}
+		}
+	}
+
+	walk(err)
+	if len(kinds) == 0 {
+		return []ErrorKind{Unknown}
+	}
+
+	return kinds
+}
+
+func (breaker *CircuitBreaker) openError(retryAfter time.Duration) *Error {
+	return &Error{
+		Code:        CircuitOpenError,
+		Message:     fmt.Sprintf("circuit %s is open", breaker.options.Name),
+		Retry:       Retryable,
+		RetryAfter:  retryAfter,
+		InstanceID:  newInstanceID(),
+		Time:        newTimestamp(),
+		Environment: newEnvironment(),
+	}
+}
@@ -0,0 +1,144 @@
+package gopherpanic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type breakerTestClock struct {
+	now time.Time
+}
+
+func (clock *breakerTestClock) Now() time.Time {
+	return clock.now
+}
+
+func newTestBreaker(clock *breakerTestClock, changes *[]string) *CircuitBreaker {
+	return NewCircuitBreaker(CircuitBreakerOptions{
+		Name:             "users",
+		FailureThreshold: 2,
+		OpenTimeout:      10 * time.Second,
+		Clock:            clock.Now,
+		OnStateChange: func(name string, from BreakerState, to BreakerState) {
+			*changes = append(*changes, fmt.Sprintf("%s: %s -> %s", name, from, to))
+		},
+	})
+}
+
+func failingTask(code Code) func(ctx context.Context) error {
+	return func(ctx context.Context) error { return New(code, "request failed") }
+}
+
+func succeedingTask(ctx context.Context) error {
+	return nil
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	clock := &breakerTestClock{now: time.Date(2024, 1, 24, 10, 0, 0, 0, time.UTC)}
+	var changes []string
+	breaker := newTestBreaker(clock, &changes)
+	ctx := context.Background()
+
+	assert.NotNil(t, breaker.Execute(ctx, failingTask(NetworkError)))
+	assert.Nil(t, breaker.Execute(ctx, succeedingTask))
+	assert.NotNil(t, breaker.Execute(ctx, failingTask(ClientError)))
+	assert.NotNil(t, breaker.Execute(ctx, failingTask(ClientError)))
+	assert.Equal(t, BreakerClosed, breaker.State())
+
+	assert.NotNil(t, breaker.Execute(ctx, failingTask(TimeoutError)))
+	assert.NotNil(t, breaker.Execute(ctx, func(ctx context.Context) error {
+		return Newf(InternalError, "cannot load user: %w", New(NetworkError, "connection reset"))
+	}))
+	assert.Equal(t, BreakerOpen, breaker.State())
+
+	clock.now = clock.now.Add(4 * time.Second)
+	called := false
+	err := breaker.Execute(ctx, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	var rejected *Error
+	assert.False(t, called)
+	assert.True(t, errors.As(err, &rejected))
+	assert.Equal(t, CircuitOpenError, rejected.Code)
+	assert.Equal(t, "circuit users is open", rejected.Message)
+	assert.Equal(t, "breaker_test.go", filepath.Base(rejected.Position.File))
+	assert.Equal(t, 62, rejected.Position.Line)
+	assert.True(t, IsRetryable(err))
+	assert.Equal(t, 6*time.Second, rejected.RetryAfter)
+
+	clock.now = clock.now.Add(6 * time.Second)
+	assert.Equal(t, BreakerHalfOpen, breaker.State())
+	assert.NotNil(t, breaker.Execute(ctx, failingTask(NetworkError)))
+	assert.Equal(t, BreakerOpen, breaker.State())
+
+	clock.now = clock.now.Add(10 * time.Second)
+	assert.Nil(t, breaker.Execute(ctx, succeedingTask))
+	assert.Equal(t, BreakerClosed, breaker.State())
+
+	assert.Equal(t, []string{
+		"users: closed -> open",
+		"users: open -> half-open",
+		"users: half-open -> open",
+		"users: open -> half-open",
+		"users: half-open -> closed",
+	}, changes)
+}
+
+func TestCircuitBreakerHalfOpenTrials(t *testing.T) {
+	clock := &breakerTestClock{now: time.Date(2024, 1, 24, 10, 0, 0, 0, time.UTC)}
+	var changes []string
+	breaker := newTestBreaker(clock, &changes)
+	ctx := context.Background()
+
+	breaker.Execute(ctx, failingTask(NetworkError))
+	breaker.Execute(ctx, failingTask(NetworkError))
+	clock.now = clock.now.Add(10 * time.Second)
+
+	err := breaker.Execute(ctx, func(ctx context.Context) error {
+		nested := breaker.Execute(ctx, succeedingTask)
+
+		var rejected *Error
+		assert.True(t, errors.As(nested, &rejected))
+		assert.Equal(t, CircuitOpenError, rejected.Code)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, BreakerClosed, breaker.State())
+}
+
+func TestCircuitBreakerTripKinds(t *testing.T) {
+	breaker := NewCircuitBreaker(CircuitBreakerOptions{Name: "disk", TripKinds: []ErrorKind{IO, Unknown}, FailureThreshold: 1})
+	ctx := context.Background()
+
+	breaker.Execute(ctx, failingTask(NetworkError))
+	assert.Equal(t, BreakerClosed, breaker.State())
+
+	breaker.Execute(ctx, func(ctx context.Context) error { return fmt.Errorf("disk full") })
+	assert.Equal(t, BreakerOpen, breaker.State())
+
+	breaker.Reset()
+	assert.Equal(t, BreakerClosed, breaker.State())
+
+	assert.Panics(t, func() {
+		breaker.Execute(ctx, func(ctx context.Context) error { panic("corrupted") })
+	})
+	assert.Equal(t, BreakerOpen, breaker.State())
+}
+
+func TestCircuitBreakerWrappedFailure(t *testing.T) {
+	breaker := NewCircuitBreaker(CircuitBreakerOptions{Name: "users", FailureThreshold: 1})
+
+	breaker.Execute(context.Background(), func(ctx context.Context) error {
+		return Wrap(InternalError, "cannot load user", New(NetworkError, "connection reset"))
+	})
+	assert.Equal(t, BreakerOpen, breaker.State())
+}
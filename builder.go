@@ -1,5 +1,7 @@
 package gopherpanic
 
+import "time"
+
 type Builder interface {
 	New() ErrorBuilder
 	Default() ErrorBuilder
@@ -14,6 +16,17 @@ type ErrorBuilder struct {
 	message  string
 	position Position
 	traces   []Trace
+	template string
+	args     Args
+
+	publicMessage string
+	correlationID string
+	instanceID    string
+	time          *time.Time
+	violations    Violations
+	severity      Severity
+	retry         RetryClass
+	retryAfter    time.Duration
 }
 
 // Create a new empty Error
@@ -52,11 +65,90 @@ func (builder ErrorBuilder) WithTraces(traces ...Trace) ErrorBuilder {
 	return builder
 }
 
+// Set the message template and its arguments.
+//
+// The message is replaced by the interpolation of the template.
+func (builder ErrorBuilder) WithTemplate(template string, args Args) ErrorBuilder {
+	builder.template = template
+	builder.args = args
+	builder.message = Interpolate(template, args)
+	return builder
+}
+
+// Set the message exposed to the clients by Error.Public
+func (builder ErrorBuilder) WithPublicMessage(message string) ErrorBuilder {
+	builder.publicMessage = message
+	return builder
+}
+
+// Set the identifier which links the client response to the logs
+func (builder ErrorBuilder) WithCorrelationID(correlationID string) ErrorBuilder {
+	builder.correlationID = correlationID
+	return builder
+}
+
+// Set the instance ID, a new one is generated by Build if it is empty and GopherpanicInstanceIDs is enabled
+func (builder ErrorBuilder) WithInstanceID(instanceID string) ErrorBuilder {
+	builder.instanceID = instanceID
+	return builder
+}
+
+// Set the creation time, the Clock time is used by Build if it is nil and GopherpanicTimestamps is enabled
+func (builder ErrorBuilder) WithTime(timestamp time.Time) ErrorBuilder {
+	builder.time = &timestamp
+	return builder
+}
+
+// Set the field violations of a validation error
+func (builder ErrorBuilder) WithViolations(violations ...Violation) ErrorBuilder {
+	builder.violations = violations
+	return builder
+}
+
+// Set the severity, SeverityError by default
+func (builder ErrorBuilder) WithSeverity(severity Severity) ErrorBuilder {
+	builder.severity = severity
+	return builder
+}
+
+// Override the retry semantic of the code
+func (builder ErrorBuilder) WithRetry(retry RetryClass) ErrorBuilder {
+	builder.retry = retry
+	return builder
+}
+
+// Set the minimum delay before the next attempt, the error becomes Retryable
+func (builder ErrorBuilder) WithRetryAfter(delay time.Duration) ErrorBuilder {
+	builder.retry = Retryable
+	builder.retryAfter = delay
+	return builder
+}
+
 func (builder ErrorBuilder) Build() Error {
+	if builder.instanceID == "" {
+		builder.instanceID = newInstanceID()
+	}
+
+	if builder.time == nil {
+		builder.time = newTimestamp()
+	}
+
 	return Error{
 		Code:     builder.code,
 		Message:  builder.message,
 		Position: builder.position,
 		Traces:   builder.traces,
+		Template: builder.template,
+		Args:     builder.args,
+
+		PublicMessage: builder.publicMessage,
+		CorrelationID: builder.correlationID,
+		InstanceID:    builder.instanceID,
+		Violations:    builder.violations,
+		Severity:      builder.severity,
+		Retry:         builder.retry,
+		RetryAfter:    builder.retryAfter,
+		Time:          builder.time,
+		Environment:   newEnvironment(),
 	}
 }
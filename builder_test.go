@@ -40,7 +40,7 @@ func TestErrorBuilderDefault(t *testing.T) {
 			want: ErrorBuilder{
 				code:     UnknownError,
 				message:  "an unexpected error occurred",
-				position: Position{File: "builder_test.go", Line: 50},
+				position: Position{File: "builder_test.go", Line: 50, Function: "github.com/ulphidius/gopherpanic.TestErrorBuilderDefault.func1", Package: "github.com/ulphidius/gopherpanic"},
 			},
 		},
 	}
@@ -271,3 +271,32 @@ func TestErrorBuilderBuild(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorBuilderWithTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		fields   ErrorBuilder
+		template string
+		args     Args
+		want     ErrorBuilder
+	}{
+		{
+			name:     "OK",
+			fields:   ErrorBuilder{message: "replaced"},
+			template: "user {user_id} not found",
+			args:     Args{"user_id": 42},
+			want: ErrorBuilder{
+				message:  "user 42 not found",
+				template: "user {user_id} not found",
+				args:     Args{"user_id": 42},
+			},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := testCase.fields.WithTemplate(testCase.template, testCase.args)
+			assert.Equal(t, testCase.want, result)
+		})
+	}
+}
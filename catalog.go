@@ -0,0 +1,299 @@
+package gopherpanic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Documentation of a registered Code
+type CatalogEntry struct {
+	Code                 // Registered code (ID and description)
+	Name        string   `json:"name"`                  // Unique identifier of the code (ex: InternalError)
+	Explanation string   `json:"explanation,omitempty"` // Long-form explanation of the error
+	Examples    []string `json:"examples,omitempty"`    // Situations or snippets which produce the error
+	Remediation string   `json:"remediation,omitempty"` // How the error can be fixed
+	HTTPStatus  int      `json:"http_status,omitempty"` // HTTP status returned for the error
+	ExitCode    int      `json:"exit_code,omitempty"`   // Process exit code used for the error
+	DocsURL     string   `json:"docs_url,omitempty"`    // Link to the online documentation
+	Template    string   `json:"template,omitempty"`    // Message template with {name} placeholders (see NewFromCatalog)
+	Retryable   bool     `json:"retryable,omitempty"`   // The failed task can be retried (see IsRetryable)
+}
+
+// Collection of documented codes indexed by ID and name
+type Catalog struct {
+	mutex   sync.RWMutex
+	entries map[ErrorKind]CatalogEntry
+	names   map[string]ErrorKind
+}
+
+type catalogFile struct {
+	Codes []CatalogEntry `json:"codes"`
+}
+
+// Catalog used by the package level Register and Lookup functions.
+//
+// It contains the gopherpanic builtin codes.
+var DefaultCatalog *Catalog = NewCatalog()
+
+func init() {
+	DefaultCatalog.MustRegister(
+		CatalogEntry{
+			Code:        UnknownError,
+			Name:        "UnknownError",
+			Explanation: "The error cannot be classified in any other code. It is also the code of errors created with ErrorBuilder.Default.",
+			Remediation: "Use a more specific code when the origin of the error is known.",
+			HTTPStatus:  500,
+		},
+		CatalogEntry{
+			Code:        IOError,
+			Name:        "IOError",
+			Explanation: "A read or write operation on a local resource (file, pipe, device) failed.",
+			Examples:    []string{"opening a file which does not exist", "writing on a full disk"},
+			Remediation: "Check that the resource exists and that the process has the required permissions.",
+			HTTPStatus:  500,
+		},
+		CatalogEntry{
+			Code:        NetworkError,
+			Name:        "NetworkError",
+			Explanation: "A remote resource cannot be reached or the connection was interrupted.",
+			Examples:    []string{"DNS resolution failure", "connection reset by peer"},
+			Remediation: "Check the connectivity with the remote service and retry the operation.",
+			HTTPStatus:  502,
+			Retryable:   true,
+		},
+		CatalogEntry{
+			Code:        InternalError,
+			Name:        "InternalError",
+			Explanation: "The application reached a state which should not happen. It usually denotes a bug.",
+			Remediation: "Report the error with its traces to the application maintainers.",
+			HTTPStatus:  500,
+		},
+		CatalogEntry{
+			Code:        ClientError,
+			Name:        "ClientError",
+			Explanation: "The request sent by the client is invalid and cannot be processed.",
+			Examples:    []string{"missing mandatory parameter", "division by 0 asked by the caller"},
+			Remediation: "Fix the request according to the error message before sending it again.",
+			HTTPStatus:  400,
+		},
+		CatalogEntry{
+			Code:        UnauthorizedError,
+			Name:        "UnauthorizedError",
+			Explanation: "The caller is not allowed to perform the task.",
+			Remediation: "Check the credentials and the permissions of the caller.",
+			HTTPStatus:  401,
+		},
+		CatalogEntry{
+			Code:        TimeoutError,
+			Name:        "TimeoutError",
+			Explanation: "The task did not complete before its deadline.",
+			Examples:    []string{"context deadline exceeded", "slow remote service"},
+			Remediation: "Increase the deadline or reduce the amount of work done by the task.",
+			HTTPStatus:  504,
+			Retryable:   true,
+		},
+		CatalogEntry{
+			Code:        UnimplementedError,
+			Name:        "UnimplementedError",
+			Explanation: "The requested behavior exists in the API but is not implemented yet.",
+			Remediation: "Avoid calling the behavior or implement it.",
+			HTTPStatus:  501,
+		},
+		CatalogEntry{
+			Code:        CircuitOpenError,
+			Name:        "CircuitOpenError",
+			Explanation: "A CircuitBreaker rejected the task because the dependency it protects failed too many times recently.",
+			Remediation: "Wait for the breaker to try the dependency again or fix the failing dependency.",
+			HTTPStatus:  503,
+			Retryable:   true,
+		},
+	)
+}
+
+// Create a new empty Catalog
+func NewCatalog() *Catalog {
+	return &Catalog{
+		entries: map[ErrorKind]CatalogEntry{},
+		names:   map[string]ErrorKind{},
+	}
+}
+
+// Parse a JSON catalog.
+//
+// Expected format: {"codes": [{"id": 100, "name": "UserNotFound", "description": "...", ...}]}
+func ParseCatalog(data []byte) (*Catalog, error) {
+	var file catalogFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, New(ClientError, fmt.Sprintf("invalid catalog: %s", err))
+	}
+
+	catalog := NewCatalog()
+	if err := catalog.Register(file.Codes...); err != nil {
+		return nil, err
+	}
+
+	return catalog, nil
+}
+
+// Load a JSON catalog from a file system.
+//
+// Used with embed.FS to ship the catalog inside the binary.
+func LoadCatalog(fsys fs.FS, name string) (*Catalog, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, New(IOError, fmt.Sprintf("cannot read catalog %s: %s", name, err))
+	}
+
+	return ParseCatalog(data)
+}
+
+// Add entries into the catalog.
+//
+// Fails without registering anything if an ID or a name is already used.
+func (catalog *Catalog) Register(entries ...CatalogEntry) error {
+	catalog.mutex.Lock()
+	defer catalog.mutex.Unlock()
+
+	ids := map[ErrorKind]bool{}
+	names := map[string]bool{}
+	for _, entry := range entries {
+		if entry.Name == "" {
+			return New(ClientError, fmt.Sprintf("code %d has no name", entry.ID))
+		}
+
+		if _, exists := catalog.entries[entry.ID]; exists || ids[entry.ID] {
+			return New(ClientError, fmt.Sprintf("code id %d is already registered", entry.ID))
+		}
+
+		name := strings.ToLower(entry.Name)
+		if _, exists := catalog.names[name]; exists || names[name] {
+			return New(ClientError, fmt.Sprintf("code name %s is already registered", entry.Name))
+		}
+
+		ids[entry.ID] = true
+		names[name] = true
+	}
+
+	for _, entry := range entries {
+		catalog.entries[entry.ID] = entry
+		catalog.names[strings.ToLower(entry.Name)] = entry.ID
+	}
+
+	return nil
+}
+
+// Same as Register but panics on failure
+func (catalog *Catalog) MustRegister(entries ...CatalogEntry) {
+	if err := catalog.Register(entries...); err != nil {
+		panic(err)
+	}
+}
+
+// Find an entry by its numeric ID or by its name (case insensitive)
+func (catalog *Catalog) Lookup(reference string) (CatalogEntry, bool) {
+	catalog.mutex.RLock()
+	defer catalog.mutex.RUnlock()
+
+	if id, err := strconv.ParseUint(reference, 10, 0); err == nil {
+		entry, exists := catalog.entries[ErrorKind(id)]
+		return entry, exists
+	}
+
+	id, exists := catalog.names[strings.ToLower(reference)]
+	if !exists {
+		return CatalogEntry{}, false
+	}
+
+	return catalog.entries[id], true
+}
+
+// Find the entry of a code
+func (catalog *Catalog) LookupCode(code Code) (CatalogEntry, bool) {
+	catalog.mutex.RLock()
+	defer catalog.mutex.RUnlock()
+
+	entry, exists := catalog.entries[code.ID]
+	return entry, exists
+}
+
+// List the entries sorted by ID
+func (catalog *Catalog) Entries() []CatalogEntry {
+	catalog.mutex.RLock()
+	defer catalog.mutex.RUnlock()
+
+	entries := make([]CatalogEntry, 0, len(catalog.entries))
+	for _, entry := range catalog.entries {
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+
+	return entries
+}
+
+// Convert into JSON with the same format as ParseCatalog input
+func (catalog *Catalog) FormatJSON(indent bool) string {
+	var data []byte
+	file := catalogFile{Codes: catalog.Entries()}
+
+	if indent {
+		data, _ = json.MarshalIndent(file, "", "\t")
+		return string(data)
+	}
+
+	data, _ = json.Marshal(file)
+	return string(data)
+}
+
+// Add entries into the DefaultCatalog
+func Register(entries ...CatalogEntry) error {
+	return DefaultCatalog.Register(entries...)
+}
+
+// Add entries into the DefaultCatalog and panics on failure
+func MustRegister(entries ...CatalogEntry) {
+	DefaultCatalog.MustRegister(entries...)
+}
+
+// Find an entry of the DefaultCatalog by its numeric ID or by its name
+func Lookup(reference string) (CatalogEntry, bool) {
+	return DefaultCatalog.Lookup(reference)
+}
+
+// Convert into the long-form explanation
+//
+// UnknownError (0): failed to perform task
+//
+// Explanation: ...
+func (entry CatalogEntry) Explain() string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "%s (%d): %s\n", entry.Name, entry.ID, entry.Description)
+	if entry.Explanation != "" {
+		fmt.Fprintf(&builder, "\nExplanation:\n\t%s\n", entry.Explanation)
+	}
+
+	if len(entry.Examples) > 0 {
+		builder.WriteString("\nExamples:\n")
+		for _, example := range entry.Examples {
+			fmt.Fprintf(&builder, "\t- %s\n", example)
+		}
+	}
+
+	if entry.Remediation != "" {
+		fmt.Fprintf(&builder, "\nRemediation:\n\t%s\n", entry.Remediation)
+	}
+
+	if entry.DocsURL != "" {
+		fmt.Fprintf(&builder, "\nSee: %s\n", entry.DocsURL)
+	}
+
+	return builder.String()
+}
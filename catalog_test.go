@@ -0,0 +1,192 @@
+package gopherpanic
+
+import (
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleCatalogEntry_Explain() {
+	entry, _ := Lookup("ClientError")
+	fmt.Print(entry.Explain())
+	// Output:
+	// ClientError (4): failed to perform client api task
+	//
+	// Explanation:
+	// 	The request sent by the client is invalid and cannot be processed.
+	//
+	// Examples:
+	// 	- missing mandatory parameter
+	// 	- division by 0 asked by the caller
+	//
+	// Remediation:
+	// 	Fix the request according to the error message before sending it again.
+}
+
+func TestCatalogRegister(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  []CatalogEntry
+		args    []CatalogEntry
+		wantErr bool
+		want    []CatalogEntry
+	}{
+		{
+			name: "OK",
+			args: []CatalogEntry{
+				{Code: Code{ID: 101, Description: "second"}, Name: "Second"},
+				{Code: Code{ID: 100, Description: "first"}, Name: "First"},
+			},
+			want: []CatalogEntry{
+				{Code: Code{ID: 100, Description: "first"}, Name: "First"},
+				{Code: Code{ID: 101, Description: "second"}, Name: "Second"},
+			},
+		},
+		{
+			name:    "KO - missing name",
+			args:    []CatalogEntry{{Code: Code{ID: 100}}},
+			wantErr: true,
+			want:    []CatalogEntry{},
+		},
+		{
+			name:    "KO - duplicated id in arguments",
+			args:    []CatalogEntry{{Code: Code{ID: 100}, Name: "First"}, {Code: Code{ID: 100}, Name: "Second"}},
+			wantErr: true,
+			want:    []CatalogEntry{},
+		},
+		{
+			name:    "KO - duplicated id in catalog",
+			fields:  []CatalogEntry{{Code: Code{ID: 100}, Name: "First"}},
+			args:    []CatalogEntry{{Code: Code{ID: 100}, Name: "Second"}},
+			wantErr: true,
+			want:    []CatalogEntry{{Code: Code{ID: 100}, Name: "First"}},
+		},
+		{
+			name:    "KO - duplicated name",
+			fields:  []CatalogEntry{{Code: Code{ID: 100}, Name: "First"}},
+			args:    []CatalogEntry{{Code: Code{ID: 101}, Name: "first"}},
+			wantErr: true,
+			want:    []CatalogEntry{{Code: Code{ID: 100}, Name: "First"}},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			catalog := NewCatalog()
+			catalog.MustRegister(testCase.fields...)
+			err := catalog.Register(testCase.args...)
+			assert.Equal(t, testCase.wantErr, err != nil)
+			assert.Equal(t, testCase.want, catalog.Entries())
+		})
+	}
+}
+
+func TestCatalogLookup(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       string
+		want       CatalogEntry
+		wantExists bool
+	}{
+		{
+			name:       "OK - by id",
+			args:       "3",
+			want:       CatalogEntry{Code: Code{ID: 3, Description: "sample"}, Name: "Sample"},
+			wantExists: true,
+		},
+		{
+			name:       "OK - by name",
+			args:       "SAMPLE",
+			want:       CatalogEntry{Code: Code{ID: 3, Description: "sample"}, Name: "Sample"},
+			wantExists: true,
+		},
+		{
+			name: "KO - unknown id",
+			args: "4",
+		},
+		{
+			name: "KO - unknown name",
+			args: "other",
+		},
+	}
+
+	catalog := NewCatalog()
+	catalog.MustRegister(CatalogEntry{Code: Code{ID: 3, Description: "sample"}, Name: "Sample"})
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, exists := catalog.Lookup(testCase.args)
+			assert.Equal(t, testCase.wantExists, exists)
+			assert.Equal(t, testCase.want, result)
+		})
+	}
+}
+
+func TestLoadCatalog(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		want    []CatalogEntry
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			args: "catalog.json",
+			want: []CatalogEntry{
+				{
+					Code:        Code{ID: 100, Description: "user not found"},
+					Name:        "UserNotFound",
+					Explanation: "the user does not exist",
+					Examples:    []string{"GET /users/42"},
+					Remediation: "create the user",
+				},
+			},
+		},
+		{
+			name:    "KO - invalid json",
+			args:    "invalid.json",
+			wantErr: true,
+		},
+		{
+			name:    "KO - missing file",
+			args:    "missing.json",
+			wantErr: true,
+		},
+	}
+
+	fsys := fstest.MapFS{
+		"catalog.json": {Data: []byte(`{"codes":[{"id":100,"name":"UserNotFound","description":"user not found","explanation":"the user does not exist","examples":["GET /users/42"],"remediation":"create the user"}]}`)},
+		"invalid.json": {Data: []byte(`{"codes":`)},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := LoadCatalog(fsys, testCase.args)
+			assert.Equal(t, testCase.wantErr, err != nil)
+			if err == nil {
+				assert.Equal(t, testCase.want, result.Entries())
+			}
+		})
+	}
+}
+
+func TestCatalogFormatJSON(t *testing.T) {
+	catalog := NewCatalog()
+	catalog.MustRegister(CatalogEntry{Code: Code{ID: 100, Description: "user not found"}, Name: "UserNotFound"})
+
+	result, err := ParseCatalog([]byte(catalog.FormatJSON(false)))
+	assert.Nil(t, err)
+	assert.Equal(t, catalog.Entries(), result.Entries())
+	assert.Equal(t, `{"codes":[{"id":100,"description":"user not found","name":"UserNotFound"}]}`, catalog.FormatJSON(false))
+}
+
+func TestDefaultCatalogReservedIDs(t *testing.T) {
+	_, exists := DefaultCatalog.LookupCode(Code{ID: Unimplemented + 1})
+	assert.False(t, exists)
+
+	entry, exists := DefaultCatalog.LookupCode(CircuitOpenError)
+	assert.True(t, exists)
+	assert.Equal(t, ErrorKind(900), entry.ID)
+}
@@ -16,6 +16,13 @@ const (
 	Unimplemented
 )
 
+// Builtin kinds added after Unimplemented.
+//
+// They use the IDs reserved from 900 to 999, so they do not collide with the user codes which extend the kinds above.
+const (
+	CircuitOpen ErrorKind = iota + 900
+)
+
 var (
 	UnknownError Code = Code{
 		ID:          Unknown,
@@ -49,6 +56,10 @@ var (
 		ID:          Unimplemented,
 		Description: "unimplemented behavior",
 	}
+	CircuitOpenError Code = Code{
+		ID:          CircuitOpen,
+		Description: "cannot perform the task, the circuit breaker is open",
+	}
 )
 
 // Type of error
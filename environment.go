@@ -0,0 +1,167 @@
+package gopherpanic
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Snapshot of the binary and of the process which produced an error
+type Environment struct {
+	Module     string `json:"module,omitempty"`   // Main module path
+	Version    string `json:"version,omitempty"`  // Main module version
+	Revision   string `json:"revision,omitempty"` // VCS revision of the build
+	Dirty      bool   `json:"dirty,omitempty"`    // The build contains uncommitted changes
+	GoVersion  string `json:"go_version"`         // Go version of the build
+	OS         string `json:"os"`                 // GOOS
+	Arch       string `json:"arch"`               // GOARCH
+	Hostname   string `json:"hostname,omitempty"` // Host name of the machine
+	PID        int    `json:"pid"`                // Process ID
+	Goroutines int    `json:"goroutines"`         // Number of goroutines when the snapshot is taken
+}
+
+// Attach the Environment to every created Error.
+//
+// Initialized from GOPHERPANIC_ENVIRONMENT (true or false). The wrapped errors do not keep their
+// environment in the traces, so only the root of a report has one.
+var GopherpanicEnvironment bool = false
+
+var (
+	staticEnvironmentOnce sync.Once
+	staticEnvironmentData Environment
+)
+
+// Build and process data which do not change during the process life
+func staticEnvironment() Environment {
+	staticEnvironmentOnce.Do(func() {
+		staticEnvironmentData = collectStaticEnvironment()
+	})
+
+	return staticEnvironmentData
+}
+
+func collectStaticEnvironment() Environment {
+	environment := Environment{
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		PID:       os.Getpid(),
+	}
+
+	environment.Hostname, _ = os.Hostname()
+
+	if info, exists := debug.ReadBuildInfo(); exists {
+		environment.Module = info.Main.Path
+		environment.Version = info.Main.Version
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				environment.Revision = setting.Value
+			case "vcs.modified":
+				environment.Dirty = setting.Value == "true"
+			}
+		}
+	}
+
+	return environment
+}
+
+func init() {
+	if enabled, err := strconv.ParseBool(os.Getenv("GOPHERPANIC_ENVIRONMENT")); err == nil {
+		GopherpanicEnvironment = enabled
+	}
+}
+
+// Snapshot of the current process.
+//
+// The build and process data are collected once, the goroutines are counted on each call.
+func CurrentEnvironment() Environment {
+	environment := staticEnvironment()
+	environment.Goroutines = runtime.NumGoroutine()
+	return environment
+}
+
+// Environment of a new Error, nil if GopherpanicEnvironment is disabled
+func newEnvironment() *Environment {
+	if !GopherpanicEnvironment {
+		return nil
+	}
+
+	environment := CurrentEnvironment()
+	return &environment
+}
+
+// Copy of the error with the current Environment
+func (err Error) WithEnvironment() Error {
+	environment := CurrentEnvironment()
+	err.Environment = &environment
+	return err
+}
+
+// Convert into a crash report: the error with its traces, its identifiers and its environment.
+//
+// The current Environment is used if the error has none.
+func (err Error) FormatCrashReport() string {
+	var builder strings.Builder
+
+	builder.WriteString(err.FormatWithTraces(false))
+	builder.WriteString("\n\n")
+
+	fmt.Fprintf(&builder, "Fingerprint: %s\n", err.Fingerprint())
+	if err.InstanceID != "" {
+		fmt.Fprintf(&builder, "Instance: %s\n", escapeText(err.InstanceID))
+	}
+	if err.CorrelationID != "" {
+		fmt.Fprintf(&builder, "Correlation ID: %s\n", escapeText(err.CorrelationID))
+	}
+	if err.Time != nil {
+		fmt.Fprintf(&builder, "Time: %s\n", err.Time.Format(absoluteTimeLayout))
+	}
+
+	environment := CurrentEnvironment()
+	if err.Environment != nil {
+		environment = *err.Environment
+	}
+
+	builder.WriteString("\nEnvironment:\n")
+	builder.WriteString(environment.Format())
+
+	return builder.String()
+}
+
+// Convert into indented lines
+//
+//	Module: github.com/user/project v1.2.0
+//	Revision: 8f2c1e7 (dirty)
+//	Go: go1.22.0 linux/amd64
+//	Host: server-1 (pid 1234)
+//	Goroutines: 12
+func (environment Environment) Format() string {
+	var builder strings.Builder
+
+	if environment.Module != "" {
+		fmt.Fprintf(&builder, "\tModule: %s", environment.Module)
+		if environment.Version != "" {
+			fmt.Fprintf(&builder, " %s", environment.Version)
+		}
+		builder.WriteString("\n")
+	}
+
+	if environment.Revision != "" {
+		fmt.Fprintf(&builder, "\tRevision: %s", environment.Revision)
+		if environment.Dirty {
+			builder.WriteString(" (dirty)")
+		}
+		builder.WriteString("\n")
+	}
+
+	fmt.Fprintf(&builder, "\tGo: %s %s/%s\n", environment.GoVersion, environment.OS, environment.Arch)
+	fmt.Fprintf(&builder, "\tHost: %s (pid %d)\n", escapeText(environment.Hostname), environment.PID)
+	fmt.Fprintf(&builder, "\tGoroutines: %d\n", environment.Goroutines)
+
+	return builder.String()
+}
@@ -0,0 +1,101 @@
+package gopherpanic
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrentEnvironment(t *testing.T) {
+	environment := CurrentEnvironment()
+
+	assert.Equal(t, "github.com/ulphidius/gopherpanic", environment.Module)
+	assert.Equal(t, runtime.Version(), environment.GoVersion)
+	assert.Equal(t, runtime.GOOS, environment.OS)
+	assert.Equal(t, runtime.GOARCH, environment.Arch)
+	assert.Equal(t, os.Getpid(), environment.PID)
+	assert.Positive(t, environment.Goroutines)
+}
+
+func TestEnvironmentAttachment(t *testing.T) {
+	enabled := GopherpanicEnvironment
+	defer func() { GopherpanicEnvironment = enabled }()
+
+	GopherpanicEnvironment = false
+	assert.Nil(t, New(IOError, "disk full").Environment)
+	assert.NotNil(t, New(IOError, "disk full").WithEnvironment().Environment)
+
+	GopherpanicEnvironment = true
+	root := New(IOError, "disk full")
+	wrapped := Wrap(InternalError, "cannot save", root)
+
+	assert.NotNil(t, root.Environment)
+	assert.NotNil(t, wrapped.Environment)
+	assert.Contains(t, wrapped.FormatJSON(false), `"environment":{"module":"github.com/ulphidius/gopherpanic",`)
+	assert.Equal(t, 1, strings.Count(wrapped.FormatJSON(false), `"environment"`))
+}
+
+func TestEnvironmentFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields Environment
+		want   string
+	}{
+		{
+			name: "OK",
+			fields: Environment{
+				Module:     "github.com/user/project",
+				Version:    "v1.2.0",
+				Revision:   "8f2c1e7",
+				Dirty:      true,
+				GoVersion:  "go1.22.0",
+				OS:         "linux",
+				Arch:       "amd64",
+				Hostname:   "server-1",
+				PID:        1234,
+				Goroutines: 12,
+			},
+			want: "\tModule: github.com/user/project v1.2.0\n\tRevision: 8f2c1e7 (dirty)\n\tGo: go1.22.0 linux/amd64\n\tHost: server-1 (pid 1234)\n\tGoroutines: 12\n",
+		},
+		{
+			name:   "OK - without build information",
+			fields: Environment{GoVersion: "go1.22.0", OS: "linux", Arch: "amd64", PID: 1},
+			want:   "\tGo: go1.22.0 linux/amd64\n\tHost:  (pid 1)\n\tGoroutines: 0\n",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, testCase.fields.Format())
+		})
+	}
+}
+
+func TestErrorFormatCrashReport(t *testing.T) {
+	timestamp := time.Date(2024, 1, 24, 10, 0, 0, 0, time.UTC)
+	err := Error{
+		Code:        IOError,
+		Message:     "disk full",
+		Position:    Position{File: "sample.go", Line: 50},
+		InstanceID:  "01ARYZ6S410000000000000000",
+		Time:        &timestamp,
+		Environment: &Environment{GoVersion: "go1.22.0", OS: "linux", Arch: "amd64", Hostname: "server-1", PID: 1234, Goroutines: 3},
+	}
+
+	assert.Equal(
+		t,
+		"sample.go:50: Error: 1:failed to perform IO task:disk full\n\n"+
+			"Fingerprint: "+err.Fingerprint()+"\n"+
+			"Instance: 01ARYZ6S410000000000000000\n"+
+			"Time: 2024-01-24T10:00:00.000Z\n\n"+
+			"Environment:\n\tGo: go1.22.0 linux/amd64\n\tHost: server-1 (pid 1234)\n\tGoroutines: 3\n",
+		err.FormatCrashReport(),
+	)
+
+	err.Environment = nil
+	assert.Contains(t, err.FormatCrashReport(), "\tModule: github.com/ulphidius/gopherpanic")
+}
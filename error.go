@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/ulphidius/iterago"
 )
@@ -31,39 +32,171 @@ func init() {
 
 // Representation of an error
 type Error struct {
-	Code     Code     `json:"code"`             // Kind of error (Internal, client, etc.)
-	Message  string   `json:"message"`          // Message which describe the user error
-	Position Position `json:"position"`         // Where the Error is spawns in the user code (Auto generation if New or Wrap is used)
-	Traces   []Trace  `json:"traces,omitempty"` // Wrapped parent errors
+	Code     Code     `json:"code"`               // Kind of error (Internal, client, etc.)
+	Message  string   `json:"message"`            // Message which describe the user error
+	Position Position `json:"position"`           // Where the Error is spawns in the user code (Auto generation if New or Wrap is used)
+	Traces   []Trace  `json:"traces,omitempty"`   // Wrapped parent errors
+	Template string   `json:"template,omitempty"` // Message template with {name} placeholders (set by NewTemplate)
+	Args     Args     `json:"args,omitempty"`     // Arguments of the message template
+
+	PublicMessage string   `json:"public_message,omitempty"` // Message safe to expose to the clients (see Public)
+	CorrelationID string   `json:"correlation_id,omitempty"` // Identifier shared by the client response and the logs
+	InstanceID    string   `json:"instance_id,omitempty"`    // Unique identifier of the occurrence (see GopherpanicInstanceIDs)
+	Severity      Severity `json:"severity,omitempty"`       // Importance of the diagnostic, SeverityError by default
+
+	Retry      RetryClass    `json:"retry,omitempty"`       // Retry semantic overriding the one of the code (see IsRetryable)
+	RetryAfter time.Duration `json:"retry_after,omitempty"` // Minimum delay before the next attempt
+
+	Violations Violations `json:"violations,omitempty"` // Invalid fields of a validation error (see NewValidation)
+
+	Time        *time.Time   `json:"time,omitempty"`        // Creation time (see GopherpanicTimestamps)
+	Environment *Environment `json:"environment,omitempty"` // Process which created the error (see GopherpanicEnvironment)
+
+	causes []error // Errors reachable by unwrapping (set by Newf and Wrapf)
+}
+
+// Error used to render a wrapped Error with its message only
+type messageError struct {
+	err *Error
+}
+
+func (wrapper messageError) Error() string {
+	return wrapper.err.Message
+}
+
+func (wrapper messageError) Unwrap() error {
+	return wrapper.err
 }
 
 // Create a new error with the user parameters and current spawn position
 func New(code Code, message string, traces ...Trace) *Error {
 	return &Error{
-		Code:     code,
-		Message:  message,
-		Position: Position{}.spawn(2),
-		Traces:   traces,
+		Code:        code,
+		Message:     message,
+		Position:    Position{}.spawn(2),
+		Traces:      traces,
+		InstanceID:  newInstanceID(),
+		Time:        newTimestamp(),
+		Environment: newEnvironment(),
 	}
 }
 
 // Create a new error that wraps an existing error.
 //
-// The build behavior is equivalent to New function.
+// The build behavior is equivalent to New function, the wrapped error is the cause of the new error.
 func Wrap(code Code, message string, err *Error) *Error {
 	newErr := ErrorBuilder{}.New().
 		WithCode(code).
 		WithMessage(message).
 		WithPosition(Position{}.spawn(2)).
 		WithTraces(append([]Trace{err.IntoTrace()}, err.Traces...)...).
+		WithInstanceID(inheritInstanceID([]error{err})).
 		Build()
+	newErr.causes = []error{err}
 	return &newErr
 }
 
+// Create a new error with a formatted message and the current spawn position.
+//
+// The format follows fmt.Errorf rules. The %w operands are the causes of the error: they are
+// reachable with errors.Is and errors.As and are converted into Traces. The message of a wrapped
+// Error is rendered without its code and position.
+func Newf(code Code, format string, args ...any) *Error {
+	message, causes := formatCauses(format, args)
+	return &Error{
+		Code:        code,
+		Message:     message,
+		Position:    Position{}.spawn(2),
+		Traces:      causesIntoTraces(causes),
+		InstanceID:  inheritInstanceID(causes),
+		Time:        newTimestamp(),
+		Environment: newEnvironment(),
+		causes:      causes,
+	}
+}
+
+// Create a new error that wraps an existing error with a formatted message.
+//
+// The build behavior is equivalent to Newf function, the wrapped error is the first cause.
+// A nil wrapped error is ignored like the nil %w operands.
+func Wrapf(code Code, err *Error, format string, args ...any) *Error {
+	message, causes := formatCauses(format, args)
+	if err != nil {
+		causes = append([]error{err}, causes...)
+	}
+	return &Error{
+		Code:        code,
+		Message:     message,
+		Position:    Position{}.spawn(2),
+		Traces:      causesIntoTraces(causes),
+		InstanceID:  inheritInstanceID(causes),
+		Time:        newTimestamp(),
+		Environment: newEnvironment(),
+		causes:      causes,
+	}
+}
+
+// Errors wrapped by Wrap, Newf and Wrapf
+func (err Error) Unwrap() []error {
+	return err.causes
+}
+
+func formatCauses(format string, args []any) (string, []error) {
+	replaced := make([]any, len(args))
+	for index, arg := range args {
+		if wrapped, isError := arg.(*Error); isError && wrapped != nil {
+			replaced[index] = messageError{err: wrapped}
+			continue
+		}
+		replaced[index] = arg
+	}
+
+	formatted := fmt.Errorf(format, replaced...)
+
+	var causes []error
+	switch unwrapper := formatted.(type) {
+	case interface{ Unwrap() error }:
+		causes = []error{unwrapper.Unwrap()}
+	case interface{ Unwrap() []error }:
+		causes = unwrapper.Unwrap()
+	}
+
+	// nil operands are rendered by fmt but are not causes
+	var result []error
+	for _, cause := range causes {
+		if wrapper, isWrapper := cause.(messageError); isWrapper {
+			cause = wrapper.err
+		}
+
+		if err, isError := cause.(*Error); cause == nil || (isError && err == nil) {
+			continue
+		}
+
+		result = append(result, cause)
+	}
+
+	return formatted.Error(), result
+}
+
+func causesIntoTraces(causes []error) []Trace {
+	var traces []Trace
+	for _, cause := range causes {
+		if err, isError := cause.(*Error); isError {
+			traces = append(append(traces, err.IntoTrace()), err.Traces...)
+			continue
+		}
+
+		traces = append(traces, Trace{Message: cause.Error()})
+	}
+
+	return traces
+}
+
 func (err Error) IntoTrace() Trace {
 	return Trace{
 		Message:  err.Message,
 		Position: err.Position,
+		Time:     err.Time,
 	}
 }
 
@@ -99,30 +232,44 @@ func (err Error) Error() string {
 // - gopherpanic format
 //
 // - GNU format
+//
+// The Violations are listed after the message, one per line.
 func (err Error) Format(custom bool, withInnerData bool) string {
+	return err.formatMessage(custom, withInnerData) + err.Violations.format(custom)
+}
+
+func (err Error) formatMessage(custom bool, withInnerData bool) string {
+	err.Message = textMessage(err.Message)
+	err.Code.Description = escapeText(err.Code.Description)
+	err.Position.File = escapeText(err.Position.TrimmedFile())
+
 	if custom {
 		if !withInnerData {
 			return fmt.Sprintf(
-				"code id: %d; description: %s\n\terror message: %s",
+				"code id: %d; description: %s%s\n\terror message: %s",
 				err.Code.ID,
 				err.Code.Description,
+				err.Severity.formatCustom(),
 				err.Message,
 			)
 		}
 
 		return fmt.Sprintf(
-			"code id: %d; description: %s\n\terror message: %s; in file: %s; at line: %d",
+			"code id: %d; description: %s%s\n\terror message: %s; in file: %s; at line: %d%s",
 			err.Code.ID,
 			err.Code.Description,
+			err.Severity.formatCustom(),
 			err.Message,
 			err.Position.File,
 			err.Position.Line,
+			err.Position.formatFunction(custom),
 		)
 	}
 
 	if !withInnerData {
 		return fmt.Sprintf(
-			"Error: %d:%s:%s",
+			"%s %d:%s:%s",
+			err.Severity.label(),
 			err.Code.ID,
 			err.Code.Description,
 			err.Message,
@@ -130,9 +277,11 @@ func (err Error) Format(custom bool, withInnerData bool) string {
 	}
 
 	return fmt.Sprintf(
-		"%s:%d: Error: %d:%s:%s",
+		"%s:%d: %s%s %d:%s:%s",
 		err.Position.File,
 		err.Position.Line,
+		err.Position.formatFunction(custom),
+		err.Severity.label(),
 		err.Code.ID,
 		err.Code.Description,
 		err.Message,
@@ -147,40 +296,65 @@ func (err Error) Format(custom bool, withInnerData bool) string {
 //
 // - GNU format
 func (err Error) FormatWithTraces(custom bool) string {
+	reference := err.oldestTime()
+	result := err.formatMessage(custom, true) + formatTime(err.Time, reference, custom) + err.Violations.format(custom)
+
 	if custom {
-		return iterago.Fold(err.Traces, err.Format(custom, true), func(acc string, trace Trace) string {
-			return acc + fmt.Sprintf("\n\t\t%s", trace.Format(custom))
+		return iterago.Fold(err.Traces, result, func(acc string, trace Trace) string {
+			return acc + fmt.Sprintf("\n\t\t%s%s", trace.Format(custom), formatTime(trace.Time, reference, custom))
 		})
 	}
 
-	return iterago.Fold(err.Traces, err.Format(custom, true), func(acc string, trace Trace) string {
-		return acc + fmt.Sprintf("\n%s", trace.Format(custom))
+	return iterago.Fold(err.Traces, result, func(acc string, trace Trace) string {
+		return acc + fmt.Sprintf("\n%s%s", trace.Format(custom), formatTime(trace.Time, reference, custom))
 	})
 }
 
+// Error fields without the MarshalJSON method
+type errorJSON Error
+
+// Convert into JSON with the DefaultRedactor applied, as the JSON handlers of the loggers do
+func (err Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(errorJSON(err.Redacted()))
+}
+
 // Convert into JSON string (with or without indentation)
 func (err Error) FormatJSON(indent bool) string {
+	return formatJSON(err, indent)
+}
+
+// Convert a value into JSON string (with or without indentation).
+//
+// A value which cannot be converted is replaced by an object holding the marshal error: {"format_error":"..."}
+func formatJSON(value any, indent bool) string {
 	var data []byte
+	var err error
 
 	if indent {
-		data, _ = json.MarshalIndent(err, "", "\t")
-		return string(data)
+		data, err = json.MarshalIndent(value, "", "\t")
+	} else {
+		data, err = json.Marshal(value)
+	}
+
+	if err != nil {
+		data, _ = json.Marshal(map[string]string{"format_error": err.Error()})
 	}
 
-	data, _ = json.Marshal(err)
 	return string(data)
 }
 
 // Representation of a parent error
 type Trace struct {
-	Message  string   `json:"message"`  // Message which describe the user error. Retrived from the Error structucture
-	Position Position `json:"position"` // Where the Error is spawns in the user code (Auto generation if New or Wrap is used). Retrived from the Error structure
+	Message  string     `json:"message"`        // Message which describe the user error. Retrived from the Error structucture
+	Position Position   `json:"position"`       // Where the Error is spawns in the user code (Auto generation if New or Wrap is used). Retrived from the Error structure
+	Time     *time.Time `json:"time,omitempty"` // Creation time of the Error. Retrived from the Error structure
 }
 
 func (trace Trace) IntoError() Error {
 	return Error{
 		Message:  trace.Message,
 		Position: trace.Position,
+		Time:     trace.Time,
 	}
 }
 
@@ -192,9 +366,12 @@ func (trace Trace) IntoError() Error {
 //
 // - GNU format
 func (trace Trace) Format(custom bool) string {
+	trace.Message = textMessage(trace.Message)
+	trace.Position.File = escapeText(trace.Position.TrimmedFile())
+
 	if custom {
-		return fmt.Sprintf("trace message: %s; in file: %s; at line: %d", trace.Message, trace.Position.File, trace.Position.Line)
+		return fmt.Sprintf("trace message: %s; in file: %s; at line: %d%s", trace.Message, trace.Position.File, trace.Position.Line, trace.Position.formatFunction(custom))
 	}
 
-	return fmt.Sprintf("%s:%d: Error: %s", trace.Position.File, trace.Position.Line, trace.Message)
+	return fmt.Sprintf("%s:%d: %sError: %s", trace.Position.File, trace.Position.Line, trace.Position.formatFunction(custom), trace.Message)
 }
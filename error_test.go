@@ -15,7 +15,7 @@ func ExampleNew() {
 	err.Position.File = filename_without_path[len(filename_without_path)-1]
 	d, _ := json.Marshal(err)
 	fmt.Println(string(d))
-	// Output: {"code":{"id":3,"description":"failed to perform application task"},"message":"message fail to compute the statistics","position":{"file":"error_test.go","line":13}}
+	// Output: {"code":{"id":3,"description":"failed to perform application task"},"message":"message fail to compute the statistics","position":{"file":"error_test.go","line":13,"function":"github.com/ulphidius/gopherpanic.ExampleNew","package":"github.com/ulphidius/gopherpanic"}}
 }
 
 func ExampleWrap() {
@@ -28,7 +28,7 @@ func ExampleWrap() {
 	newErr.Position.File = filename_without_path[len(filename_without_path)-1]
 	d, _ := json.Marshal(newErr)
 	fmt.Println(string(d))
-	// Output: {"code":{"id":3,"description":"failed to perform application task"},"message":"fail to fetch statistics data","position":{"file":"error_test.go","line":26},"traces":[{"message":"message fail to compute the statistics","position":{"file":"error_test.go","line":22}}]}
+	// Output: {"code":{"id":3,"description":"failed to perform application task"},"message":"fail to fetch statistics data","position":{"file":"error_test.go","line":26,"function":"github.com/ulphidius/gopherpanic.ExampleWrap","package":"github.com/ulphidius/gopherpanic"},"traces":[{"message":"message fail to compute the statistics","position":{"file":"error_test.go","line":22,"function":"github.com/ulphidius/gopherpanic.ExampleWrap","package":"github.com/ulphidius/gopherpanic"}}]}
 }
 
 func ExampleError_IntoTrace() {
@@ -37,7 +37,7 @@ func ExampleError_IntoTrace() {
 	trace.Position.File = filename_without_path[len(filename_without_path)-1]
 	d, _ := json.Marshal(trace)
 	fmt.Println(string(d))
-	// Output: {"message":"message fail to compute the statistics","position":{"file":"error_test.go","line":35}}
+	// Output: {"message":"message fail to compute the statistics","position":{"file":"error_test.go","line":35,"function":"github.com/ulphidius/gopherpanic.ExampleError_IntoTrace","package":"github.com/ulphidius/gopherpanic"}}
 
 }
 
@@ -79,7 +79,7 @@ func ExampleError_Format_withoutInnerData() {
 	//	error message: fail to fetch statistics data
 }
 
-func ExampleError_Format_GNUWithInnerData() {
+func ExampleError_Format_gnuWithInnerData() {
 	err := New(InternalError, "message fail to compute the statistics")
 	filename_without_path := strings.Split(err.Position.File, "/")
 	err.Position.File = filename_without_path[len(filename_without_path)-1]
@@ -92,7 +92,7 @@ func ExampleError_Format_GNUWithInnerData() {
 	// Output: error_test.go:87: Error: 3:failed to perform application task:fail to fetch statistics data
 }
 
-func ExampleError_Format_GNUWithoutInnerData() {
+func ExampleError_Format_gnuWithoutInnerData() {
 	err := New(InternalError, "message fail to compute the statistics")
 	filename_without_path := strings.Split(err.Position.File, "/")
 	err.Position.File = filename_without_path[len(filename_without_path)-1]
@@ -131,7 +131,7 @@ func ExampleError_FormatWithTraces() {
 	// 		trace message: message fail to compute the statistics; in file: error_test.go; at line: 109
 }
 
-func ExampleError_FormatWithTraces_GNU() {
+func ExampleError_FormatWithTraces_gnu() {
 	err := New(InternalError, "message fail to compute the statistics")
 	filename_without_path := strings.Split(err.Position.File, "/")
 	err.Position.File = filename_without_path[len(filename_without_path)-1]
@@ -174,7 +174,7 @@ func ExampleError_FormatJSON() {
 	newErr3.Position.File = filename_without_path[len(filename_without_path)-1]
 
 	fmt.Println(newErr3.FormatJSON(false))
-	// Output: {"code":{"id":3,"description":"failed to perform application task"},"message":"fail to fetch statistics data","position":{"file":"error_test.go","line":172},"traces":[{"message":"fail to fetch statistics data","position":{"file":"error_test.go","line":168}},{"message":"fail to fetch statistics data","position":{"file":"error_test.go","line":164}},{"message":"message fail to compute the statistics","position":{"file":"error_test.go","line":160}}]}
+	// Output: {"code":{"id":3,"description":"failed to perform application task"},"message":"fail to fetch statistics data","position":{"file":"error_test.go","line":172,"function":"github.com/ulphidius/gopherpanic.ExampleError_FormatJSON","package":"github.com/ulphidius/gopherpanic"},"traces":[{"message":"fail to fetch statistics data","position":{"file":"error_test.go","line":168,"function":"github.com/ulphidius/gopherpanic.ExampleError_FormatJSON","package":"github.com/ulphidius/gopherpanic"}},{"message":"fail to fetch statistics data","position":{"file":"error_test.go","line":164,"function":"github.com/ulphidius/gopherpanic.ExampleError_FormatJSON","package":"github.com/ulphidius/gopherpanic"}},{"message":"message fail to compute the statistics","position":{"file":"error_test.go","line":160,"function":"github.com/ulphidius/gopherpanic.ExampleError_FormatJSON","package":"github.com/ulphidius/gopherpanic"}}]}
 }
 
 func ExampleTrace_Format() {
@@ -183,7 +183,7 @@ func ExampleTrace_Format() {
 	// Output: trace message: error database; in file: error_test.go; at line: 828
 }
 
-func ExampleTrace_Format_GNU() {
+func ExampleTrace_Format_gnu() {
 	trace := Trace{Message: "error database", Position: Position{File: "error_test.go", Line: 828}}
 	fmt.Println(trace.Format(false))
 	// Output: error_test.go:828: Error: error database
@@ -234,8 +234,10 @@ func TestNew(t *testing.T) {
 				Code:    UnknownError,
 				Message: "sample error",
 				Position: Position{
-					File: "error_test.go",
-					Line: 284,
+					File:     "error_test.go",
+					Line:     288,
+					Function: "github.com/ulphidius/gopherpanic.TestNew.func1",
+					Package:  "github.com/ulphidius/gopherpanic",
 				},
 				Traces: []Trace{
 					{
@@ -272,8 +274,10 @@ func TestNew(t *testing.T) {
 				Code:    UnknownError,
 				Message: "sample error",
 				Position: Position{
-					File: "error_test.go",
-					Line: 284,
+					File:     "error_test.go",
+					Line:     288,
+					Function: "github.com/ulphidius/gopherpanic.TestNew.func1",
+					Package:  "github.com/ulphidius/gopherpanic",
 				},
 			},
 		},
@@ -341,8 +345,10 @@ func TestWrap(t *testing.T) {
 				Code:    UnknownError,
 				Message: "sample error",
 				Position: Position{
-					File: "error_test.go",
-					Line: 383,
+					File:     "error_test.go",
+					Line:     389,
+					Function: "github.com/ulphidius/gopherpanic.TestWrap.func1",
+					Package:  "github.com/ulphidius/gopherpanic",
 				},
 				Traces: []Trace{
 					{
@@ -381,8 +387,8 @@ func TestWrap(t *testing.T) {
 	for _, testCase := range tests {
 		t.Run(testCase.name, func(t *testing.T) {
 			result := Wrap(testCase.args.code, testCase.args.message, testCase.args.err) // Error check based on the current line
-			files := strings.Split(result.Position.File, "/")
-			result.Position.File = files[len(files)-1]
+			result.Position.File = result.Position.File[strings.LastIndex(result.Position.File, "/")+1:]
+			testCase.want.causes = []error{testCase.args.err}
 			assert.Equal(t, testCase.want, result)
 		})
 	}
@@ -863,3 +869,148 @@ func TestTraceFormat(t *testing.T) {
 		})
 	}
 }
+
+func ExampleNewf() {
+	err := New(IOError, "cannot open statistics.csv")
+	err.Position = Position{File: "statistics.go", Line: 12}
+
+	newErr := Newf(InternalError, "fail to compute the statistics of %s: %w", "march", err)
+	newErr.Position = Position{File: "main.go", Line: 30}
+
+	fmt.Println(newErr.FormatWithTraces(false))
+	// Output:
+	// main.go:30: Error: 3:failed to perform application task:fail to compute the statistics of march: cannot open statistics.csv
+	// statistics.go:12: Error: cannot open statistics.csv
+}
+
+func TestNewf(t *testing.T) {
+	cause := &Error{
+		Code:     IOError,
+		Message:  "inner 1",
+		Position: Position{File: "inner_1.go", Line: 10},
+		Traces:   []Trace{{Message: "inner 2", Position: Position{File: "inner_2.go", Line: 20}}},
+	}
+	standard := fmt.Errorf("standard error")
+
+	type args struct {
+		format string
+		args   []any
+	}
+
+	tests := []struct {
+		name       string
+		args       args
+		want       *Error
+		wantCauses []error
+	}{
+		{
+			name: "OK - without cause",
+			args: args{format: "user %d not found", args: []any{42}},
+			want: &Error{
+				Code:     UnknownError,
+				Message:  "user 42 not found",
+				Position: Position{File: "error_test.go", Line: 943, Function: "github.com/ulphidius/gopherpanic.TestNewf.func1", Package: "github.com/ulphidius/gopherpanic"},
+			},
+		},
+		{
+			name: "OK - with causes",
+			args: args{format: "sample error: %w; %w", args: []any{cause, standard}},
+			want: &Error{
+				Code:     UnknownError,
+				Message:  "sample error: inner 1; standard error",
+				Position: Position{File: "error_test.go", Line: 943, Function: "github.com/ulphidius/gopherpanic.TestNewf.func1", Package: "github.com/ulphidius/gopherpanic"},
+				Traces: []Trace{
+					{Message: "inner 1", Position: Position{File: "inner_1.go", Line: 10}},
+					{Message: "inner 2", Position: Position{File: "inner_2.go", Line: 20}},
+					{Message: "standard error"},
+				},
+			},
+			wantCauses: []error{cause, standard},
+		},
+		{
+			name: "OK - nil cause",
+			args: args{format: "sample error: %w", args: []any{(*Error)(nil)}},
+			want: &Error{
+				Code:     UnknownError,
+				Message:  "sample error: <nil>",
+				Position: Position{File: "error_test.go", Line: 943, Function: "github.com/ulphidius/gopherpanic.TestNewf.func1", Package: "github.com/ulphidius/gopherpanic"},
+			},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := Newf(UnknownError, testCase.args.format, testCase.args.args...) // Error check based on the current line
+			files := strings.Split(result.Position.File, "/")
+			result.Position.File = files[len(files)-1]
+			assert.Equal(t, testCase.wantCauses, result.Unwrap())
+			for _, cause := range testCase.wantCauses {
+				assert.ErrorIs(t, result, cause)
+			}
+			result.causes = nil
+			assert.Equal(t, testCase.want, result)
+		})
+	}
+}
+
+func TestWrapf(t *testing.T) {
+	wrapped := &Error{Code: IOError, Message: "error message", Position: Position{File: "error.go", Line: 10}}
+	standard := fmt.Errorf("standard error")
+
+	result := Wrapf(UnknownError, wrapped, "sample error %s: %w", "format", standard) // Error check based on the current line
+	files := strings.Split(result.Position.File, "/")
+	result.Position.File = files[len(files)-1]
+
+	assert.Equal(t, []error{wrapped, standard}, result.Unwrap())
+	assert.ErrorIs(t, result, wrapped)
+	assert.ErrorIs(t, result, standard)
+	assert.Equal(t, "sample error format: standard error", result.Message)
+	assert.Equal(t, Position{File: "error_test.go", Line: 960, Function: "github.com/ulphidius/gopherpanic.TestWrapf", Package: "github.com/ulphidius/gopherpanic"}, result.Position)
+	assert.Equal(t, []Trace{
+		{Message: "error message", Position: Position{File: "error.go", Line: 10}},
+		{Message: "standard error"},
+	}, result.Traces)
+}
+
+func TestWrapfCauses(t *testing.T) {
+	wrapped := &Error{Code: IOError, Message: "error message"}
+	standard := fmt.Errorf("standard error")
+
+	tests := []struct {
+		name       string
+		err        *Error
+		args       []any
+		wantCauses []error
+		wantTraces []Trace
+	}{
+		{
+			name:       "OK - wrapped error and cause",
+			err:        wrapped,
+			args:       []any{standard},
+			wantCauses: []error{wrapped, standard},
+			wantTraces: []Trace{{Message: "error message"}, {Message: "standard error"}},
+		},
+		{
+			name:       "OK - nil wrapped error",
+			err:        nil,
+			args:       []any{standard},
+			wantCauses: []error{standard},
+			wantTraces: []Trace{{Message: "standard error"}},
+		},
+		{
+			name:       "OK - nil wrapped error and cause",
+			err:        nil,
+			args:       []any{nil},
+			wantCauses: nil,
+			wantTraces: nil,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := Wrapf(InternalError, testCase.err, "sample error: %w", testCase.args...)
+			assert.Equal(t, testCase.wantCauses, result.Unwrap())
+			assert.Equal(t, testCase.wantTraces, result.Traces)
+		})
+	}
+}
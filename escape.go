@@ -0,0 +1,104 @@
+package gopherpanic
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Escape the newlines and control characters of the fields rendered by the text formats.
+//
+// A message which contains a newline or an ANSI escape code cannot forge an extra log line.
+// The JSON format always keeps the raw values.
+var GopherpanicEscapeText bool = true
+
+// Maximum number of characters of a message rendered by the text formats, 0 for no limit.
+//
+// Initialized from GOPHERPANIC_MAX_MESSAGE_LENGTH. The JSON format always keeps the whole message.
+var GopherpanicMaxMessageLength int = 0
+
+func init() {
+	length, err := strconv.Atoi(os.Getenv("GOPHERPANIC_MAX_MESSAGE_LENGTH"))
+	if err != nil || length < 0 {
+		return
+	}
+
+	GopherpanicMaxMessageLength = length
+}
+
+// Prepare a message for the text formats: redaction, truncation and escape.
+//
+// The raw message is truncated before the escape so the cut never splits an escape sequence.
+func textMessage(message string) string {
+	return escapeText(truncateText(redact(message)))
+}
+
+// Escape a field for the text formats
+//
+// "line\n/etc/passwd:1: Error: forged" -> "line\\n/etc/passwd:1: Error: forged"
+func escapeText(text string) string {
+	if !GopherpanicEscapeText || !needsEscape(text) {
+		return text
+	}
+
+	var builder strings.Builder
+	for _, character := range text {
+		switch character {
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\r':
+			builder.WriteString(`\r`)
+		case '\t':
+			builder.WriteString(`\t`)
+		default:
+			if !isUnsafe(character) {
+				builder.WriteRune(character)
+				continue
+			}
+
+			if character < 0x80 {
+				fmt.Fprintf(&builder, `\x%02x`, character)
+				continue
+			}
+
+			fmt.Fprintf(&builder, `\u%04x`, character)
+		}
+	}
+
+	return builder.String()
+}
+
+func needsEscape(text string) bool {
+	for _, character := range text {
+		if isUnsafe(character) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Control characters (C0, DEL, C1) and Unicode line or paragraph separators
+func isUnsafe(character rune) bool {
+	return unicode.IsControl(character) || character == '\u2028' || character == '\u2029'
+}
+
+// Cut a text at GopherpanicMaxMessageLength characters with an explicit marker
+//
+// "a very long message" -> "a very...[truncated 13 characters]"
+func truncateText(text string) string {
+	if GopherpanicMaxMessageLength <= 0 {
+		return text
+	}
+
+	length := utf8.RuneCountInString(text)
+	if length <= GopherpanicMaxMessageLength {
+		return text
+	}
+
+	kept := []rune(text)[:GopherpanicMaxMessageLength]
+	return fmt.Sprintf("%s...[truncated %d characters]", string(kept), length-GopherpanicMaxMessageLength)
+}
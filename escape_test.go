@@ -0,0 +1,154 @@
+package gopherpanic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want string
+	}{
+		{
+			name: "OK - forged line",
+			args: "bad input\nmain.go:1: Error: 3:forged",
+			want: `bad input\nmain.go:1: Error: 3:forged`,
+		},
+		{
+			name: "OK - ANSI escape code",
+			args: "\x1b[31mred\x1b[0m\r\t",
+			want: `\x1b[31mred\x1b[0m\r\t`,
+		},
+		{
+			name: "OK - C1 control and line separator",
+			args: "a\u0085b\u2028c",
+			want: `a\u0085b\u2028c`,
+		},
+		{
+			name: "OK - printable unicode",
+			args: "échec: 文件 introuvable",
+			want: "échec: 文件 introuvable",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, escapeText(testCase.args))
+		})
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields int
+		args   string
+		want   string
+	}{
+		{
+			name:   "OK - no limit",
+			fields: 0,
+			args:   "a very long message",
+			want:   "a very long message",
+		},
+		{
+			name:   "OK - truncated",
+			fields: 6,
+			args:   "a very long message",
+			want:   "a very...[truncated 13 characters]",
+		},
+		{
+			name:   "OK - multibyte characters",
+			fields: 2,
+			args:   "été",
+			want:   "ét...[truncated 1 characters]",
+		},
+		{
+			name:   "OK - under the limit",
+			fields: 20,
+			args:   "a very long message",
+			want:   "a very long message",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			maxMessageLength := GopherpanicMaxMessageLength
+			GopherpanicMaxMessageLength = testCase.fields
+			defer func() { GopherpanicMaxMessageLength = maxMessageLength }()
+
+			assert.Equal(t, testCase.want, truncateText(testCase.args))
+		})
+	}
+}
+
+func TestErrorFormatEscape(t *testing.T) {
+	err := Error{
+		Code:     ClientError,
+		Message:  "bad input\nsample.go:1: Error: 3:forged",
+		Position: Position{File: "sample.go", Line: 50},
+		Traces:   []Trace{{Message: "\x1b[2Jcleared", Position: Position{File: "sample.go", Line: 40}}},
+	}
+
+	tests := []struct {
+		name   string
+		format func() string
+		want   string
+	}{
+		{
+			name:   "OK - FormatWithTraces",
+			format: func() string { return err.FormatWithTraces(false) },
+			want:   "sample.go:50: Error: 4:failed to perform client api task:bad input\\nsample.go:1: Error: 3:forged\nsample.go:40: Error: \\x1b[2Jcleared",
+		},
+		{
+			name:   "OK - FormatJSON keeps the raw message",
+			format: func() string { return err.FormatJSON(false) },
+			want:   `{"code":{"id":4,"description":"failed to perform client api task"},"message":"bad input\nsample.go:1: Error: 3:forged","position":{"file":"sample.go","line":50},"traces":[{"message":"\u001b[2Jcleared","position":{"file":"sample.go","line":40}}]}`,
+		},
+		{
+			name: "OK - truncated",
+			format: func() string {
+				maxMessageLength := GopherpanicMaxMessageLength
+				GopherpanicMaxMessageLength = 9
+				defer func() { GopherpanicMaxMessageLength = maxMessageLength }()
+				return err.Format(false, false)
+			},
+			want: "Error: 4:failed to perform client api task:bad input...[truncated 29 characters]",
+		},
+		{
+			name: "OK - truncated on an escaped character",
+			format: func() string {
+				maxMessageLength := GopherpanicMaxMessageLength
+				GopherpanicMaxMessageLength = 10
+				defer func() { GopherpanicMaxMessageLength = maxMessageLength }()
+				return err.Format(false, false)
+			},
+			want: "Error: 4:failed to perform client api task:bad input\\n...[truncated 28 characters]",
+		},
+		{
+			name: "OK - escape disabled",
+			format: func() string {
+				GopherpanicEscapeText = false
+				defer func() { GopherpanicEscapeText = true }()
+				return err.Format(false, false)
+			},
+			want: "Error: 4:failed to perform client api task:bad input\nsample.go:1: Error: 3:forged",
+		},
+		{
+			name: "OK - public correlation ID",
+			format: func() string {
+				return PublicError{Title: "failed", Code: 4, CorrelationID: "abc\nforged"}.Format()
+			},
+			want: `Error: 4:failed (correlation id: abc\nforged)`,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, testCase.format())
+		})
+	}
+}
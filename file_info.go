@@ -1,27 +1,104 @@
 package gopherpanic
 
-import "runtime"
+import (
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
 
-// Reprentation of spawn position in the code
+// Render the function of the positions in the text formats.
+//
+// Initialized from GOPHERPANIC_SHOW_FUNCTION (true or false).
+//
+// - GNU: sample.go:50: in main.compute: Error: 0:failed to perform task:sample error
+//
+// - Custom: ...; in file: sample.go; at line: 50; in function: main.compute
+var GopherpanicShowFunction bool = false
+
+func init() {
+	if show, err := strconv.ParseBool(os.Getenv("GOPHERPANIC_SHOW_FUNCTION")); err == nil {
+		GopherpanicShowFunction = show
+	}
+}
+
+// Reprentation of spawn position in the code, without column (runtime.Caller only reports the file and the line)
 type Position struct {
-	File string `json:"file"` // Filepath where the error is spawned
-	Line int    `json:"line"` // Line where the error is spawned
+	File     string `json:"file"`               // Filepath where the error is spawned
+	Line     int    `json:"line"`               // Line where the error is spawned
+	Function string `json:"function,omitempty"` // Fully qualified name of the function where the error is spawned
+	Package  string `json:"package,omitempty"`  // Import path of the package where the error is spawned
 }
 
 // Create a new position with the data of where the method is called
 func (position Position) Spawn() Position {
-	_, file, line, _ := runtime.Caller(1)
-	return Position{
-		File: file,
-		Line: line,
-	}
+	pc, file, line, _ := runtime.Caller(1)
+	return newPosition(pc, file, line)
 }
 
 // Used to fetch position data of where the parent function which calls this method is called itself
 func (position Position) spawn(parentLevel int) Position {
-	_, file, line, _ := runtime.Caller(parentLevel)
-	return Position{
+	pc, file, line, _ := runtime.Caller(parentLevel)
+	return newPosition(pc, file, line)
+}
+
+// Create a new position with the data of where the function which calls this method is called.
+//
+// skip is the number of additional stack frames to ascend, 0 is equivalent to Spawn.
+// Used by generated constructors to report the position of their caller.
+func (position Position) SpawnCaller(skip int) Position {
+	return position.spawn(skip + 2)
+}
+
+func newPosition(pc uintptr, file string, line int) Position {
+	position := Position{
 		File: file,
 		Line: line,
 	}
+
+	if function := runtime.FuncForPC(pc); function != nil {
+		position.Function = strings.ReplaceAll(function.Name(), "%2e", ".")
+		position.Package = packagePath(function.Name())
+	}
+
+	return position
+}
+
+// Import path of a fully qualified function name as returned by the runtime
+//
+// github.com/user/project/store.(*Users).Find -> github.com/user/project/store
+//
+// gopkg.in/yaml%2ev3.Unmarshal -> gopkg.in/yaml.v3
+func packagePath(function string) string {
+	slash := strings.LastIndex(function, "/")
+	dot := strings.Index(function[slash+1:], ".")
+	if dot < 0 {
+		return ""
+	}
+
+	return strings.ReplaceAll(function[:slash+1+dot], "%2e", ".")
+}
+
+// Function name qualified by the package name
+//
+// github.com/user/project/store.(*Users).Find -> store.(*Users).Find
+func (position Position) ShortFunction() string {
+	if position.Package == "" {
+		return position.Function
+	}
+
+	return strings.TrimPrefix(position.Function, position.Package[:strings.LastIndex(position.Package, "/")+1])
+}
+
+// Function rendered by the text formats, empty if GopherpanicShowFunction is disabled
+func (position Position) formatFunction(custom bool) string {
+	if !GopherpanicShowFunction || position.Function == "" {
+		return ""
+	}
+
+	if custom {
+		return "; in function: " + escapeText(position.ShortFunction())
+	}
+
+	return "in " + escapeText(position.ShortFunction()) + ": "
 }
@@ -19,8 +19,10 @@ func TestPositionSpawn(t *testing.T) {
 			name:   "OK",
 			fields: Position{},
 			want: Position{
-				File: "file_info_test.go",
-				Line: 30,
+				File:     "file_info_test.go",
+				Line:     32,
+				Function: "github.com/ulphidius/gopherpanic.TestPositionSpawn.func1",
+				Package:  "github.com/ulphidius/gopherpanic",
 			},
 		},
 	}
@@ -47,8 +49,10 @@ func TestPositionPrivateSpawn(t *testing.T) {
 			fields: Position{},
 			args:   0,
 			want: Position{
-				File: "file_info.go",
-				Line: 22,
+				File:     "file_info.go",
+				Line:     41,
+				Function: "github.com/ulphidius/gopherpanic.Position.spawn",
+				Package:  "github.com/ulphidius/gopherpanic",
 			},
 		},
 		{
@@ -56,8 +60,10 @@ func TestPositionPrivateSpawn(t *testing.T) {
 			fields: Position{},
 			args:   1,
 			want: Position{
-				File: "file_info_test.go",
-				Line: 67,
+				File:     "file_info_test.go",
+				Line:     73,
+				Function: "github.com/ulphidius/gopherpanic.TestPositionPrivateSpawn.func1",
+				Package:  "github.com/ulphidius/gopherpanic",
 			},
 		},
 	}
@@ -72,12 +78,144 @@ func TestPositionPrivateSpawn(t *testing.T) {
 	}
 }
 
-func ExampleSpawn() {
+func ExamplePosition_Spawn() {
 	pos := Position{}.Spawn()
 
 	filename_without_path := strings.Split(pos.File, "/")
 	pos.File = filename_without_path[len(filename_without_path)-1]
 	d, _ := json.Marshal(pos)
 	fmt.Println(string(d))
-	// Output: {"file":"file_info_test.go","line":76}
+	// Output: {"file":"file_info_test.go","line":82,"function":"github.com/ulphidius/gopherpanic.ExamplePosition_Spawn","package":"github.com/ulphidius/gopherpanic"}
+}
+
+func TestPositionSpawnCaller(t *testing.T) {
+	tests := []struct {
+		name string
+		args int
+		want Position
+	}{
+		{
+			name: "OK - equivalent to Spawn",
+			args: 0,
+			want: Position{
+				File:     "file_info_test.go",
+				Line:     130,
+				Function: "github.com/ulphidius/gopherpanic.spawnCallerHelper",
+				Package:  "github.com/ulphidius/gopherpanic",
+			},
+		},
+		{
+			name: "OK - caller of the test function",
+			args: 1,
+			want: Position{
+				File:     "file_info_test.go",
+				Line:     121,
+				Function: "github.com/ulphidius/gopherpanic.TestPositionSpawnCaller.func1",
+				Package:  "github.com/ulphidius/gopherpanic",
+			},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := spawnCallerHelper(testCase.args) // Error check based on the current line
+			files := strings.Split(result.File, "/")
+			result.File = files[len(files)-1]
+			assert.Equal(t, testCase.want, result)
+		})
+	}
+}
+
+func spawnCallerHelper(skip int) Position {
+	return Position{}.SpawnCaller(skip) // Error check based on the current line
+}
+
+func TestPackagePath(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want string
+	}{
+		{
+			name: "OK - function",
+			args: "github.com/user/project/store.Find",
+			want: "github.com/user/project/store",
+		},
+		{
+			name: "OK - method",
+			args: "github.com/user/project/store.(*Users).Find",
+			want: "github.com/user/project/store",
+		},
+		{
+			name: "OK - closure in main",
+			args: "main.main.func1",
+			want: "main",
+		},
+		{
+			name: "OK - dotted module path",
+			args: "gopkg.in/yaml%2ev3.Unmarshal",
+			want: "gopkg.in/yaml.v3",
+		},
+		{
+			name: "OK - no package",
+			args: "",
+			want: "",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, packagePath(testCase.args))
+		})
+	}
+}
+
+func TestPositionShortFunction(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields Position
+		want   string
+	}{
+		{
+			name:   "OK",
+			fields: Position{Function: "github.com/user/project/store.(*Users).Find", Package: "github.com/user/project/store"},
+			want:   "store.(*Users).Find",
+		},
+		{
+			name:   "OK - main package",
+			fields: Position{Function: "main.main", Package: "main"},
+			want:   "main.main",
+		},
+		{
+			name:   "OK - unknown package",
+			fields: Position{Function: "compute"},
+			want:   "compute",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, testCase.fields.ShortFunction())
+		})
+	}
+}
+
+func TestFormatShowFunction(t *testing.T) {
+	GopherpanicShowFunction = true
+	defer func() { GopherpanicShowFunction = false }()
+
+	position := Position{File: "sample.go", Line: 50, Function: "github.com/user/project/stats.compute", Package: "github.com/user/project/stats"}
+	err := Error{Code: InternalError, Message: "sample error", Position: position, Traces: []Trace{{Message: "sample trace", Position: position}}}
+
+	assert.Equal(
+		t,
+		"sample.go:50: in stats.compute: Error: 3:failed to perform application task:sample error\nsample.go:50: in stats.compute: Error: sample trace",
+		err.FormatWithTraces(false),
+	)
+	assert.Equal(
+		t,
+		"code id: 3; description: failed to perform application task\n\terror message: sample error; in file: sample.go; at line: 50; in function: stats.compute\n\t\ttrace message: sample trace; in file: sample.go; at line: 50; in function: stats.compute",
+		err.FormatWithTraces(true),
+	)
+	assert.Equal(t, "Error: 3:failed to perform application task:sample error", err.Format(false, false))
 }
@@ -0,0 +1,85 @@
+package gopherpanic
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"regexp"
+)
+
+// Data used to compute the fingerprints
+type FingerprintOptions struct {
+	Lines bool // Include the position lines, the fingerprint changes when the code above the error is edited
+}
+
+// Options used by Error.Fingerprint
+var GopherpanicFingerprint FingerprintOptions = FingerprintOptions{}
+
+type normalizationRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// Variable parts of the messages, applied in order
+var normalizationRules = []normalizationRule{
+	{pattern: regexp.MustCompile(`"[^"]*"|'[^']*'|` + "`[^`]*`"), replacement: "<string>"},
+	{pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`), replacement: "<email>"},
+	{pattern: regexp.MustCompile(`\b[a-z][a-z0-9+.\-]*://\S+`), replacement: "<url>"},
+	{pattern: regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), replacement: "<uuid>"},
+	{pattern: regexp.MustCompile(`\b\d{1,3}(?:\.\d{1,3}){3}(?::\d+)?\b`), replacement: "<ip>"},
+	{pattern: regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b|\b[0-9a-f]*[0-9][0-9a-f]*[a-f][0-9a-f]*\b|\b[0-9a-f]*[a-f][0-9a-f]*[0-9][0-9a-f]*\b`), replacement: "<hex>"},
+	{pattern: regexp.MustCompile(`[-+]?\b\d+(?:\.\d+)?(?:ns|us|µs|ms|s|m|h)?\b`), replacement: "<number>"},
+}
+
+// Replace the variable data of a message (numbers, quoted strings, UUIDs, e-mails, ...) by placeholders
+//
+// `user 42 not found in "users"` -> `user <number> not found in <string>`
+func NormalizeMessage(message string) string {
+	for _, rule := range normalizationRules {
+		message = rule.pattern.ReplaceAllLiteralString(message, rule.replacement)
+	}
+
+	return message
+}
+
+// Identifier of the logical error computed with GopherpanicFingerprint.
+//
+// See FingerprintWith.
+func (err Error) Fingerprint() string {
+	return err.FingerprintWith(GopherpanicFingerprint)
+}
+
+// Identifier of the logical error: the same code, message template (or normalized message)
+// and chain of positions always produce the same fingerprint across processes and machines.
+//
+// The positions are identified by their package, file base name and function, which do not depend on the
+// checkout directory, GOPATH or trim prefixes.
+func (err Error) FingerprintWith(options FingerprintOptions) string {
+	message := err.Template
+	if message == "" {
+		message = NormalizeMessage(err.Message)
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "code:%d\nmessage:%s\n", err.Code.ID, message)
+
+	positions := []Position{err.Position}
+	for _, trace := range err.Traces {
+		positions = append(positions, trace.Position)
+	}
+
+	for _, position := range positions {
+		if position.File == "" && position.Function == "" {
+			continue
+		}
+
+		fmt.Fprintf(hash, "position:%s %s %s", position.Package, filepath.Base(position.File), position.Function)
+		if options.Lines {
+			fmt.Fprintf(hash, ":%d", position.Line)
+		}
+		hash.Write([]byte("\n"))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)[:16])
+}
@@ -0,0 +1,122 @@
+package gopherpanic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want string
+	}{
+		{
+			name: "OK - numbers and strings",
+			args: `user 42 not found in "users" after 1.5s`,
+			want: `user <number> not found in <string> after <number>`,
+		},
+		{
+			name: "OK - identifiers",
+			args: "order 3f2b8c1e-9a4d-4e6b-8c2a-1d5e7f9a0b3c of john@example.com rejected by 10.0.0.12:8080",
+			want: "order <uuid> of <email> rejected by <ip>",
+		},
+		{
+			name: "OK - hexadecimal and url",
+			args: "commit 8f2c1e7 at 0xc000123456 from https://example.com/api?id=3",
+			want: "commit <hex> at <hex> from <url>",
+		},
+		{
+			name: "OK - words are kept",
+			args: "table1 is read only",
+			want: "table1 is read only",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, NormalizeMessage(testCase.args))
+		})
+	}
+}
+
+func fingerprintSample(id int) *Error {
+	return Newf(ClientError, "user %d not found", id)
+}
+
+func TestErrorFingerprint(t *testing.T) {
+	position := Position{File: "/home/runner/work/project/store.go", Line: 12, Function: "example.com/project.Find"}
+
+	tests := []struct {
+		name  string
+		left  Error
+		right Error
+		equal bool
+	}{
+		{
+			name:  "OK - variable data",
+			left:  *fingerprintSample(1),
+			right: *fingerprintSample(2),
+			equal: true,
+		},
+		{
+			name:  "OK - same template",
+			left:  *NewTemplate(ClientError, "user {id} not found", Args{"id": "bob"}),
+			right: *NewTemplate(ClientError, "user {id} not found", Args{"id": "alice"}),
+			equal: true,
+		},
+		{
+			name:  "OK - line drift",
+			left:  Error{Code: IOError, Message: "disk full", Position: position},
+			right: Error{Code: IOError, Message: "disk full", Position: Position{File: position.File, Line: 20, Function: position.Function}},
+			equal: true,
+		},
+		{
+			name:  "OK - different code",
+			left:  Error{Code: IOError, Message: "disk full", Position: position},
+			right: Error{Code: InternalError, Message: "disk full", Position: position},
+			equal: false,
+		},
+		{
+			name:  "OK - different trace chain",
+			left:  Error{Code: IOError, Message: "disk full", Position: position},
+			right: Error{Code: IOError, Message: "disk full", Position: position, Traces: []Trace{{Message: "write", Position: position}}},
+			equal: false,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.equal, testCase.left.Fingerprint() == testCase.right.Fingerprint())
+		})
+	}
+}
+
+func TestErrorFingerprintWith(t *testing.T) {
+	err := Error{Code: IOError, Message: "disk full", Position: Position{File: "/home/runner/work/project/store.go", Line: 12, Function: "example.com/project.Find"}}
+	moved := err
+	moved.Position.Line = 20
+
+	assert.Equal(t, "92126850312dcee3dac525d21e9354d9", err.Fingerprint())
+	assert.Len(t, err.FingerprintWith(FingerprintOptions{Lines: true}), 32)
+	assert.NotEqual(t, err.FingerprintWith(FingerprintOptions{Lines: true}), moved.FingerprintWith(FingerprintOptions{Lines: true}))
+}
+
+func TestErrorFingerprintEnvironment(t *testing.T) {
+	defer func() { trimPrefixes = nil }()
+
+	err := New(IOError, "disk full")
+	cached := Error{Code: IOError, Message: "disk full", Position: Position{File: "/home/runner/go/pkg/mod/example.com/project@v1.2.0/store.go", Function: "example.com/project.Find", Package: "example.com/project"}}
+	fingerprint, cachedFingerprint := err.Fingerprint(), cached.Fingerprint()
+
+	t.Setenv("GOPATH", "/home/runner/go")
+	t.Setenv("GOMODCACHE", "/home/runner/go/pkg/mod")
+	AddTrimPrefix("/home/runner", "/")
+	assert.Equal(t, fingerprint, err.Fingerprint())
+	assert.Equal(t, cachedFingerprint, cached.Fingerprint())
+
+	moved := cached
+	moved.Position.File = "/tmp/vendor/example.com/project/store.go"
+	assert.Equal(t, cachedFingerprint, moved.Fingerprint())
+}
@@ -0,0 +1,172 @@
+package gopherpanic
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+type GroupMode uint
+
+const (
+	FailFast   GroupMode = iota // The first error cancels the context of the other tasks and is the only reported error
+	CollectAll                  // Every task runs until its end and every error is reported
+)
+
+// Collection of concurrent tasks whose errors are aggregated into a MultiError.
+//
+// Similar to golang.org/x/sync/errgroup: the tasks errors are wrapped with the task name (also
+// stored in the "task" Args entry), the panics are recovered into InternalError and the concurrency can be bounded.
+type Group struct {
+	ctx       context.Context
+	mode      GroupMode
+	cancel    context.CancelCauseFunc
+	wait      sync.WaitGroup
+	semaphore chan struct{}
+
+	mutex  sync.Mutex
+	errors MultiError
+}
+
+// Create a Group and the context given to its tasks.
+//
+// The context is canceled by the first error in FailFast mode and when Wait returns.
+func NewGroup(ctx context.Context, mode GroupMode) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{ctx: ctx, mode: mode, cancel: cancel}, ctx
+}
+
+// Limit the number of running tasks, Go blocks until a task ends when the limit is reached.
+//
+// Must be called before the first Go call, a negative limit removes the bound.
+func (group *Group) SetLimit(limit int) {
+	if limit < 0 {
+		group.semaphore = nil
+		return
+	}
+
+	group.semaphore = make(chan struct{}, limit)
+}
+
+// Run a named task in a new goroutine with the context of the Group.
+//
+// The task error is wrapped with the task name at the position of the Go call.
+func (group *Group) Go(name string, task func(ctx context.Context) error) {
+	position := Position{}.spawn(2)
+
+	if group.semaphore != nil {
+		group.semaphore <- struct{}{}
+	}
+
+	group.wait.Add(1)
+	go func() {
+		defer func() {
+			if group.semaphore != nil {
+				<-group.semaphore
+			}
+			group.wait.Done()
+		}()
+
+		group.report(group.run(name, position, task))
+	}()
+}
+
+// Wait the end of the tasks and return their errors as a *MultiError, nil if every task succeeded
+func (group *Group) Wait() error {
+	group.wait.Wait()
+	group.cancel(nil)
+
+	group.mutex.Lock()
+	defer group.mutex.Unlock()
+
+	return group.errors.ErrorOrNil()
+}
+
+func (group *Group) report(err error) {
+	if err == nil {
+		return
+	}
+
+	group.mutex.Lock()
+	defer group.mutex.Unlock()
+
+	if group.mode == FailFast {
+		if group.errors.Len() > 0 {
+			return
+		}
+		group.cancel(err)
+	}
+
+	group.errors.Add(err)
+}
+
+// Run a task and convert its panic into InternalError
+func (group *Group) run(name string, position Position, task func(ctx context.Context) error) (err error) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+
+		panicked := &Error{
+			Code:        InternalError,
+			Message:     fmt.Sprintf("task %s panicked: %v", name, recovered),
+			Args:        Args{"task": name},
+			Position:    position,
+			Traces:      []Trace{{Message: fmt.Sprintf("panic: %v", recovered), Position: panicPosition()}},
+			InstanceID:  newInstanceID(),
+			Time:        newTimestamp(),
+			Environment: newEnvironment(),
+		}
+		if cause, isError := recovered.(error); isError {
+			panicked.causes = []error{cause}
+		}
+
+		err = panicked
+	}()
+
+	return taskError(name, position, task(group.ctx))
+}
+
+// Wrap the error of a task with its name, the code of the first Error of the chain is kept
+func taskError(name string, position Position, err error) error {
+	if converted, isError := err.(*Error); err == nil || (isError && converted == nil) {
+		return nil
+	}
+
+	var wrapped *Error
+	switch err := err.(type) {
+	case *Error:
+		wrapped = Wrapf(err.Code, err, "task %s failed", name)
+	default:
+		wrapped = Newf(memberCode(err), "task %s failed: %w", name, err)
+	}
+
+	wrapped.Position = position
+	wrapped.Args = Args{"task": name}
+	return wrapped
+}
+
+// Position of the function which panicked, called by the deferred function of Group.run
+func panicPosition() Position {
+	programCounters := make([]uintptr, 32)
+	frames := runtime.CallersFrames(programCounters[:runtime.Callers(2, programCounters)])
+
+	panicking := false
+	for {
+		frame, more := frames.Next()
+		if panicking && !strings.HasPrefix(frame.Function, "runtime.") {
+			return Position{File: frame.File, Line: frame.Line, Function: frame.Function, Package: packagePath(frame.Function)}
+		}
+
+		if frame.Function == "runtime.gopanic" {
+			panicking = true
+		}
+
+		if !more {
+			return Position{}
+		}
+	}
+}
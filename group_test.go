@@ -0,0 +1,146 @@
+package gopherpanic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupSuccess(t *testing.T) {
+	group, _ := NewGroup(context.Background(), CollectAll)
+	for index := 0; index < 4; index++ {
+		group.Go(fmt.Sprintf("task-%d", index), func(ctx context.Context) error { return nil })
+	}
+
+	assert.Nil(t, group.Wait())
+}
+
+func TestGroupCollectAll(t *testing.T) {
+	sentinel := fmt.Errorf("connection refused")
+	group, _ := NewGroup(context.Background(), CollectAll)
+	group.Go("users", func(ctx context.Context) error { return New(ClientError, "name is required") })
+	group.Go("cache", func(ctx context.Context) error { return sentinel })
+	group.Go("orders", func(ctx context.Context) error { return nil })
+
+	err := group.Wait()
+	var multi *MultiError
+	assert.True(t, errors.As(err, &multi))
+	assert.Equal(t, 2, multi.Len())
+	assert.ErrorIs(t, err, sentinel)
+
+	messages := map[string]Code{}
+	for _, member := range multi.Errors {
+		converted := member.(*Error)
+		messages[converted.Message] = converted.Code
+		assert.Equal(t, "group_test.go", filepath.Base(converted.Position.File))
+	}
+
+	assert.Equal(t, map[string]Code{
+		"task users failed":                     ClientError,
+		"task cache failed: connection refused": UnknownError,
+	}, messages)
+}
+
+func TestGroupFailFast(t *testing.T) {
+	group, ctx := NewGroup(context.Background(), FailFast)
+	group.Go("failing", func(ctx context.Context) error { return New(IOError, "disk full") })
+	group.Go("waiting", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	err := group.Wait()
+	var multi *MultiError
+	assert.True(t, errors.As(err, &multi))
+	assert.Equal(t, 1, multi.Len())
+	assert.Equal(t, "task failing failed", multi.Errors[0].(*Error).Message)
+	assert.Equal(t, multi.Errors[0], context.Cause(ctx))
+}
+
+func TestGroupPanic(t *testing.T) {
+	sentinel := fmt.Errorf("invalid state")
+	group, _ := NewGroup(context.Background(), CollectAll)
+	group.Go("worker", func(ctx context.Context) error { panic(sentinel) })
+
+	err := group.Wait()
+	var converted *Error
+	assert.True(t, errors.As(err, &converted))
+	assert.Equal(t, InternalError, converted.Code)
+	assert.Equal(t, "task worker panicked: invalid state", converted.Message)
+	assert.ErrorIs(t, err, sentinel)
+	assert.Len(t, converted.Traces, 1)
+	assert.Equal(t, "panic: invalid state", converted.Traces[0].Message)
+	assert.Contains(t, converted.Traces[0].Position.Function, "TestGroupPanic")
+	assert.Equal(t, 69, converted.Traces[0].Position.Line)
+}
+
+func TestGroupSetLimit(t *testing.T) {
+	var running, maximum atomic.Int32
+	group, _ := NewGroup(context.Background(), CollectAll)
+	group.SetLimit(2)
+
+	for index := 0; index < 6; index++ {
+		group.Go(fmt.Sprintf("task-%d", index), func(ctx context.Context) error {
+			current := running.Add(1)
+			for {
+				previous := maximum.Load()
+				if current <= previous || maximum.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			return nil
+		})
+	}
+
+	assert.Nil(t, group.Wait())
+	assert.LessOrEqual(t, maximum.Load(), int32(2))
+}
+
+func TestGroupPanicEnvironment(t *testing.T) {
+	enabled := GopherpanicEnvironment
+	defer func() { GopherpanicEnvironment = enabled }()
+	GopherpanicEnvironment = true
+
+	group, _ := NewGroup(context.Background(), CollectAll)
+	group.Go("worker", func(ctx context.Context) error { panic("invalid state") })
+
+	var converted *Error
+	assert.True(t, errors.As(group.Wait(), &converted))
+	assert.Equal(t, "task worker panicked: invalid state", converted.Message)
+	assert.NotNil(t, converted.Environment)
+	assert.NotEmpty(t, converted.Environment.GoVersion)
+}
+
+func TestGroupTaskError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected Code
+	}{
+		{name: "OK - Error", err: New(NetworkError, "connection refused"), expected: NetworkError},
+		{name: "OK - wrapped Error", err: fmt.Errorf("sync: %w", New(NetworkError, "connection refused")), expected: NetworkError},
+		{name: "OK - standard error", err: errors.New("connection refused"), expected: UnknownError},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			group, _ := NewGroup(context.Background(), CollectAll)
+			group.Go("sync", func(ctx context.Context) error { return testCase.err })
+
+			var converted *Error
+			assert.True(t, errors.As(group.Wait(), &converted))
+			assert.Equal(t, testCase.expected, converted.Code)
+			assert.Equal(t, Args{"task": "sync"}, converted.Args)
+			assert.True(t, errors.Is(converted, testCase.err))
+		})
+	}
+}
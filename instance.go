@@ -0,0 +1,107 @@
+package gopherpanic
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Generate an instance ID for every created Error.
+//
+// Initialized from GOPHERPANIC_INSTANCE_IDS (true or false).
+var GopherpanicInstanceIDs bool = false
+
+// Generator of the instance IDs, replaceable for deterministic tests
+var InstanceIDGenerator func() string = NewULID
+
+// Crockford's base32 alphabet used by the ULIDs
+const ulidAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+var ulidState struct {
+	mutex     sync.Mutex
+	timestamp uint64
+	entropy   [10]byte
+}
+
+func init() {
+	if enabled, err := strconv.ParseBool(os.Getenv("GOPHERPANIC_INSTANCE_IDS")); err == nil {
+		GopherpanicInstanceIDs = enabled
+	}
+}
+
+// Create a ULID: 26 characters sortable by creation time (millisecond timestamp followed by 80 random bits).
+//
+// The IDs created in the same millisecond are incremented to stay sorted.
+func NewULID() string {
+	ulidState.mutex.Lock()
+	defer ulidState.mutex.Unlock()
+
+	timestamp := uint64(time.Now().UnixMilli())
+	switch {
+	case timestamp > ulidState.timestamp:
+		_, _ = rand.Read(ulidState.entropy[:])
+	case incrementEntropy(&ulidState.entropy):
+		timestamp = ulidState.timestamp
+	default:
+		// The entropy overflowed, the ID moves to the next millisecond
+		timestamp = ulidState.timestamp + 1
+		_, _ = rand.Read(ulidState.entropy[:])
+	}
+	ulidState.timestamp = timestamp
+
+	return encodeULID(timestamp, ulidState.entropy)
+}
+
+func encodeULID(timestamp uint64, entropy [10]byte) string {
+	var data [16]byte
+	binary.BigEndian.PutUint16(data[0:2], uint16(timestamp>>32))
+	binary.BigEndian.PutUint32(data[2:6], uint32(timestamp))
+	copy(data[6:], entropy[:])
+
+	// 128 bits encoded in 26 characters of 5 bits, the first character holds 3 bits
+	result := make([]byte, 26)
+	high := binary.BigEndian.Uint64(data[0:8])
+	low := binary.BigEndian.Uint64(data[8:16])
+	for index := 25; index >= 0; index-- {
+		result[index] = ulidAlphabet[low&31]
+		low = low>>5 | high<<59
+		high >>= 5
+	}
+
+	return string(result)
+}
+
+// Add 1 to the entropy, returns false on overflow
+func incrementEntropy(entropy *[10]byte) bool {
+	for index := len(entropy) - 1; index >= 0; index-- {
+		entropy[index]++
+		if entropy[index] != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Instance ID of a new Error, empty if GopherpanicInstanceIDs is disabled
+func newInstanceID() string {
+	if !GopherpanicInstanceIDs || InstanceIDGenerator == nil {
+		return ""
+	}
+
+	return InstanceIDGenerator()
+}
+
+// Instance ID inherited from the first cause which has one, a new one otherwise
+func inheritInstanceID(causes []error) string {
+	for _, cause := range causes {
+		if err, isError := cause.(*Error); isError && err.InstanceID != "" {
+			return err.InstanceID
+		}
+	}
+
+	return newInstanceID()
+}
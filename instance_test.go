@@ -0,0 +1,116 @@
+package gopherpanic
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func enableInstanceIDs(t *testing.T) {
+	enabled, generator := GopherpanicInstanceIDs, InstanceIDGenerator
+	t.Cleanup(func() { GopherpanicInstanceIDs, InstanceIDGenerator = enabled, generator })
+
+	counter := 0
+	GopherpanicInstanceIDs = true
+	InstanceIDGenerator = func() string {
+		counter++
+		return fmt.Sprintf("ID%d", counter)
+	}
+}
+
+func TestEncodeULID(t *testing.T) {
+	tests := []struct {
+		name      string
+		timestamp uint64
+		entropy   [10]byte
+		want      string
+	}{
+		{
+			name:      "OK - zero",
+			timestamp: 0,
+			want:      "00000000000000000000000000",
+		},
+		{
+			name:      "OK - maximum",
+			timestamp: 1<<48 - 1,
+			entropy:   [10]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			want:      "7ZZZZZZZZZZZZZZZZZZZZZZZZZ",
+		},
+		{
+			name:      "OK - timestamp",
+			timestamp: 1469918176385,
+			want:      "01ARYZ6S410000000000000000",
+		},
+		{
+			name:      "OK - entropy",
+			timestamp: 0,
+			entropy:   [10]byte{9: 33},
+			want:      "00000000000000000000000011",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, encodeULID(testCase.timestamp, testCase.entropy))
+		})
+	}
+}
+
+func TestIncrementEntropy(t *testing.T) {
+	entropy := [10]byte{9: 0xff}
+	assert.True(t, incrementEntropy(&entropy))
+	assert.Equal(t, [10]byte{8: 1}, entropy)
+
+	entropy = [10]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	assert.False(t, incrementEntropy(&entropy))
+	assert.Equal(t, [10]byte{}, entropy)
+}
+
+func TestNewULID(t *testing.T) {
+	ids := make([]string, 1000)
+	for index := range ids {
+		ids[index] = NewULID()
+	}
+
+	assert.Len(t, ids[0], 26)
+	assert.True(t, sort.StringsAreSorted(ids))
+
+	unique := map[string]bool{}
+	for _, id := range ids {
+		unique[id] = true
+	}
+	assert.Len(t, unique, len(ids))
+}
+
+func TestInstanceID(t *testing.T) {
+	enableInstanceIDs(t)
+
+	root := New(IOError, "disk full")
+	wrapped := Wrap(InternalError, "cannot save", root)
+	formatted := Wrapf(InternalError, wrapped, "cannot save user %d", 42)
+	other := Newf(ClientError, "invalid user: %w", fmt.Errorf("standard error"))
+	built := ErrorBuilder{}.New().WithCode(ClientError).Build()
+	forced := ErrorBuilder{}.New().WithInstanceID("support-42").Build()
+	converted := Newf(UnknownError, "%w", fmt.Errorf("standard error"))
+
+	assert.Equal(t, "ID1", root.InstanceID)
+	assert.Equal(t, "ID1", wrapped.InstanceID)
+	assert.Equal(t, "ID1", formatted.InstanceID)
+	assert.Equal(t, "ID2", other.InstanceID)
+	assert.Equal(t, "ID3", built.InstanceID)
+	assert.Equal(t, "support-42", forced.InstanceID)
+	assert.Equal(t, "ID4", converted.InstanceID)
+	assert.Equal(t, "ID1", root.Public().Instance)
+	assert.Contains(t, root.FormatJSON(false), `"instance_id":"ID1"`)
+}
+
+func TestInstanceIDDisabled(t *testing.T) {
+	assert.Empty(t, New(IOError, "disk full").InstanceID)
+}
+
+func TestPublicErrorFormatInstance(t *testing.T) {
+	public := PublicError{Title: "failed to perform application task", Code: 3, CorrelationID: "7f3a", Instance: "01ARYZ6S410000000000000000"}
+	assert.Equal(t, "Error: 3:failed to perform application task (correlation id: 7f3a, instance: 01ARYZ6S410000000000000000)", public.Format())
+}
@@ -0,0 +1,325 @@
+package gopherpanic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Locale used when no locale is given by the context.
+//
+// Initialized from GOPHERPANIC_LOCALE, the canonical messages are written in English.
+var GopherpanicLocale string = "en"
+
+// Localizer used by Error.Localized
+var DefaultLocalizer *Localizer = NewLocalizer()
+
+func init() {
+	if locale := os.Getenv("GOPHERPANIC_LOCALE"); locale != "" {
+		GopherpanicLocale = locale
+	}
+}
+
+type localeKey struct{}
+
+// Translation of a message.
+//
+// The One form is used when the "count" argument is 1, the Other form otherwise.
+// In a message catalog file a plain string is equivalent to the Other form.
+type Translation struct {
+	One   string `json:"one,omitempty"`
+	Other string `json:"other"`
+}
+
+// Translations of a locale.
+//
+// Codes are keyed by the name registered in the DefaultCatalog, messages are keyed by
+// their template (or their message when they have no template).
+type MessageCatalog struct {
+	Codes    map[string]string      `json:"codes,omitempty"`    // Translated code descriptions
+	Messages map[string]Translation `json:"messages,omitempty"` // Translated message templates
+}
+
+// Collection of message catalogs indexed by locale
+type Localizer struct {
+	mutex    sync.RWMutex
+	catalogs map[string]MessageCatalog
+}
+
+func (translation *Translation) UnmarshalJSON(data []byte) error {
+	var other string
+	if err := json.Unmarshal(data, &other); err == nil {
+		translation.Other = other
+		return nil
+	}
+
+	type plain Translation
+	return json.Unmarshal(data, (*plain)(translation))
+}
+
+// Create a new Localizer without translations
+func NewLocalizer() *Localizer {
+	return &Localizer{catalogs: map[string]MessageCatalog{}}
+}
+
+// Add the translations of a locale, existing keys are replaced
+func (localizer *Localizer) Add(locale string, catalog MessageCatalog) {
+	localizer.mutex.Lock()
+	defer localizer.mutex.Unlock()
+
+	locale = normalizeLocale(locale)
+	current := localizer.catalogs[locale]
+	if current.Codes == nil {
+		current.Codes = map[string]string{}
+	}
+	if current.Messages == nil {
+		current.Messages = map[string]Translation{}
+	}
+
+	for name, description := range catalog.Codes {
+		current.Codes[name] = description
+	}
+	for id, translation := range catalog.Messages {
+		current.Messages[id] = translation
+	}
+
+	localizer.catalogs[locale] = current
+}
+
+// Load the JSON message catalogs matching a pattern (ex: "locales/*.json").
+//
+// The locale is the file name without extension (fr.json, pt-BR.json).
+// Used with embed.FS to ship the translations inside the binary.
+func (localizer *Localizer) Load(fsys fs.FS, pattern string) error {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return New(ClientError, fmt.Sprintf("invalid message catalog pattern %s: %s", pattern, err))
+	}
+
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return New(IOError, fmt.Sprintf("cannot read message catalog %s: %s", name, err))
+		}
+
+		var catalog MessageCatalog
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			return New(ClientError, fmt.Sprintf("invalid message catalog %s: %s", name, err))
+		}
+
+		localizer.Add(strings.TrimSuffix(path.Base(name), path.Ext(name)), catalog)
+	}
+
+	return nil
+}
+
+// List the locales which have translations
+func (localizer *Localizer) Locales() []string {
+	localizer.mutex.RLock()
+	defer localizer.mutex.RUnlock()
+
+	locales := make([]string, 0, len(localizer.catalogs))
+	for locale := range localizer.catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	return locales
+}
+
+// Translate a message template and interpolate its arguments.
+//
+// The locale falls back on its base language (pt-br -> pt). Returns false if no translation exists.
+func (localizer *Localizer) Translate(locale string, id string, args Args) (string, bool) {
+	localizer.mutex.RLock()
+	defer localizer.mutex.RUnlock()
+
+	for _, candidate := range localeCandidates(locale) {
+		translation, exists := localizer.catalogs[candidate].Messages[id]
+		if !exists {
+			continue
+		}
+
+		if translation.One != "" && isOne(args["count"]) {
+			return Interpolate(translation.One, args), true
+		}
+
+		return Interpolate(translation.Other, args), true
+	}
+
+	return "", false
+}
+
+// Translate the description of a registered code name
+func (localizer *Localizer) TranslateCode(locale string, name string) (string, bool) {
+	localizer.mutex.RLock()
+	defer localizer.mutex.RUnlock()
+
+	for _, candidate := range localeCandidates(locale) {
+		if description, exists := localizer.catalogs[candidate].Codes[name]; exists {
+			return description, true
+		}
+	}
+
+	return "", false
+}
+
+// Choose the best available locale for an Accept-Language header.
+//
+// Returns GopherpanicLocale if no locale matches.
+func (localizer *Localizer) Match(acceptLanguage string) string {
+	localizer.mutex.RLock()
+	defer localizer.mutex.RUnlock()
+
+	for _, locale := range ParseAcceptLanguage(acceptLanguage) {
+		for _, candidate := range localeCandidates(locale) {
+			if _, exists := localizer.catalogs[candidate]; exists {
+				return candidate
+			}
+		}
+	}
+
+	return GopherpanicLocale
+}
+
+// List the locales of an Accept-Language header ordered by preference
+//
+// "fr-CH, fr;q=0.9, en;q=0.8" -> [fr-ch fr en]
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale  string
+		quality float64
+	}
+
+	locales := []weighted{}
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		locale := normalizeLocale(fields[0])
+		if locale == "" || locale == "*" {
+			continue
+		}
+
+		quality := 1.0
+		for _, parameter := range fields[1:] {
+			value, found := strings.CutPrefix(strings.TrimSpace(parameter), "q=")
+			if !found {
+				continue
+			}
+
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err == nil {
+				quality = parsed
+			}
+		}
+
+		if quality > 0 {
+			locales = append(locales, weighted{locale: locale, quality: quality})
+		}
+	}
+
+	sort.SliceStable(locales, func(i, j int) bool {
+		return locales[i].quality > locales[j].quality
+	})
+
+	result := make([]string, 0, len(locales))
+	for _, locale := range locales {
+		result = append(result, locale.locale)
+	}
+
+	return result
+}
+
+// Store the locale used by LocalizedContext in a context
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// Locale stored by WithLocale, GopherpanicLocale if the context has no locale
+func LocaleFromContext(ctx context.Context) string {
+	if locale, exists := ctx.Value(localeKey{}).(string); exists && locale != "" {
+		return locale
+	}
+
+	return GopherpanicLocale
+}
+
+// Copy of the error with the code description, the messages and the trace messages translated
+// with the DefaultLocalizer.
+//
+// The untranslated texts are kept in their canonical (English) version.
+func (err Error) Localized(locale string) Error {
+	localized := err
+	if entry, exists := DefaultCatalog.LookupCode(err.Code); exists {
+		if description, exists := DefaultLocalizer.TranslateCode(locale, entry.Name); exists {
+			localized.Code.Description = description
+		}
+	}
+
+	id := err.Template
+	if id == "" {
+		id = err.Message
+	}
+
+	if message, exists := DefaultLocalizer.Translate(locale, id, err.Args); exists {
+		localized.Message = message
+	}
+
+	if message, exists := DefaultLocalizer.Translate(locale, err.PublicMessage, err.Args); exists {
+		localized.PublicMessage = message
+	}
+
+	if len(err.Traces) > 0 {
+		localized.Traces = make([]Trace, len(err.Traces))
+		for index, trace := range err.Traces {
+			if message, exists := DefaultLocalizer.Translate(locale, trace.Message, nil); exists {
+				trace.Message = message
+			}
+			localized.Traces[index] = trace
+		}
+	}
+
+	return localized
+}
+
+// Same as Localized with the locale of the context
+func (err Error) LocalizedContext(ctx context.Context) Error {
+	return err.Localized(LocaleFromContext(ctx))
+}
+
+// Locale followed by its base language (pt-br -> [pt-br pt])
+func localeCandidates(locale string) []string {
+	locale = normalizeLocale(locale)
+	if base, _, found := strings.Cut(locale, "-"); found {
+		return []string{locale, base}
+	}
+
+	return []string{locale}
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+func isOne(count any) bool {
+	switch count := count.(type) {
+	case int:
+		return count == 1
+	case int64:
+		return count == 1
+	case uint:
+		return count == 1
+	case float64:
+		return count == 1
+	case string:
+		return count == "1"
+	default:
+		return false
+	}
+}
@@ -0,0 +1,176 @@
+package gopherpanic
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var localeTestFS = fstest.MapFS{
+	"locales/fr.json": {Data: []byte(`{
+		"codes": {"ClientError": "échec de la tâche de l'API client"},
+		"messages": {
+			"user {user_id} not found in {table}": "utilisateur {user_id} introuvable dans {table}",
+			"{count} files are missing": {"one": "{count} fichier manquant", "other": "{count} fichiers manquants"},
+			"an unexpected error occurred": "une erreur inattendue est survenue"
+		}
+	}`)},
+	"locales/fr-CA.json": {Data: []byte(`{"codes": {"ClientError": "échec de la tâche du client"}}`)},
+}
+
+func init() {
+	if err := DefaultLocalizer.Load(localeTestFS, "locales/*.json"); err != nil {
+		panic(err)
+	}
+}
+
+func ExampleError_Localized() {
+	err := NewTemplate(ClientError, "user {user_id} not found in {table}", Args{"user_id": 42, "table": "users"})
+	err.Position = Position{File: "main.go", Line: 30}
+
+	fmt.Println(err.Localized("fr").Error())
+	fmt.Println(err.Error())
+	// Output:
+	// main.go:30: Error: 4:échec de la tâche de l'API client:utilisateur 42 introuvable dans users
+	// main.go:30: Error: 4:failed to perform client api task:user 42 not found in users
+}
+
+func TestErrorLocalized(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields Error
+		args   string
+		want   Error
+	}{
+		{
+			name:   "OK - template",
+			fields: Error{Code: ClientError, Message: "user 42 not found in users", Template: "user {user_id} not found in {table}", Args: Args{"user_id": 42, "table": "users"}},
+			args:   "fr",
+			want:   Error{Code: Code{ID: Client, Description: "échec de la tâche de l'API client"}, Message: "utilisateur 42 introuvable dans users", Template: "user {user_id} not found in {table}", Args: Args{"user_id": 42, "table": "users"}},
+		},
+		{
+			name:   "OK - plural one",
+			fields: Error{Code: IOError, Message: "1 files are missing", Template: "{count} files are missing", Args: Args{"count": 1}},
+			args:   "fr",
+			want:   Error{Code: IOError, Message: "1 fichier manquant", Template: "{count} files are missing", Args: Args{"count": 1}},
+		},
+		{
+			name:   "OK - plural other",
+			fields: Error{Code: IOError, Message: "3 files are missing", Template: "{count} files are missing", Args: Args{"count": 3}},
+			args:   "fr",
+			want:   Error{Code: IOError, Message: "3 fichiers manquants", Template: "{count} files are missing", Args: Args{"count": 3}},
+		},
+		{
+			name:   "OK - region and base language fallback",
+			fields: Error{Code: ClientError, Message: "an unexpected error occurred", Traces: []Trace{{Message: "an unexpected error occurred"}}},
+			args:   "fr_CA",
+			want:   Error{Code: Code{ID: Client, Description: "échec de la tâche du client"}, Message: "une erreur inattendue est survenue", Traces: []Trace{{Message: "une erreur inattendue est survenue"}}},
+		},
+		{
+			name:   "OK - english fallback",
+			fields: Error{Code: ClientError, Message: "an unexpected error occurred"},
+			args:   "de",
+			want:   Error{Code: ClientError, Message: "an unexpected error occurred"},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := testCase.fields.Localized(testCase.args)
+			assert.Equal(t, testCase.want, result)
+		})
+	}
+}
+
+func TestErrorLocalizedContext(t *testing.T) {
+	err := Error{Code: ClientError, Message: "an unexpected error occurred", Traces: []Trace{{Message: "an unexpected error occurred"}}}
+
+	result := err.LocalizedContext(WithLocale(context.Background(), DefaultLocalizer.Match("de-DE, fr;q=0.8, en;q=0.5")))
+	assert.Equal(t, "une erreur inattendue est survenue", result.Message)
+	assert.Equal(t, "une erreur inattendue est survenue", result.Traces[0].Message)
+	assert.Equal(t, "an unexpected error occurred", err.Traces[0].Message)
+
+	result = err.LocalizedContext(context.Background())
+	assert.Equal(t, err, result)
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want []string
+	}{
+		{
+			name: "OK",
+			args: "fr-CH, fr;q=0.9, en;q=0.8, de;q=0.7, *;q=0.5",
+			want: []string{"fr-ch", "fr", "en", "de"},
+		},
+		{
+			name: "OK - unordered qualities",
+			args: "en;q=0.5, pt_BR, es;q=0",
+			want: []string{"pt-br", "en"},
+		},
+		{
+			name: "OK - empty",
+			args: "",
+			want: []string{},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, ParseAcceptLanguage(testCase.args))
+		})
+	}
+}
+
+func TestLocalizerMatch(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want string
+	}{
+		{name: "OK - region", args: "fr-CA", want: "fr-ca"},
+		{name: "OK - base language", args: "fr-BE, en", want: "fr"},
+		{name: "OK - default locale", args: "de", want: "en"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, DefaultLocalizer.Match(testCase.args))
+		})
+	}
+}
+
+func TestLocalizerLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    fstest.MapFS
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			args: localeTestFS,
+			want: []string{"fr", "fr-ca"},
+		},
+		{
+			name:    "KO - invalid catalog",
+			args:    fstest.MapFS{"locales/fr.json": {Data: []byte(`{"codes": []}`)}},
+			want:    []string{},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			localizer := NewLocalizer()
+			err := localizer.Load(testCase.args, "locales/*.json")
+			assert.Equal(t, testCase.wantErr, err != nil)
+			assert.Equal(t, testCase.want, localizer.Locales())
+		})
+	}
+}
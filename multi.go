@@ -0,0 +1,272 @@
+package gopherpanic
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type CodeStrategy uint
+
+const (
+	MostSevereCode CodeStrategy = iota // Code with the highest HTTP status in the DefaultCatalog, the first one on equality
+	FirstCode                          // Code of the first error
+	FixedCode                          // MultiError.Aggregate code
+)
+
+// Collection of errors compatible with errors.Join (errors.Is and errors.As inspect every member).
+//
+// The zero value is an empty collection which uses the MostSevereCode strategy and renders every member.
+type MultiError struct {
+	Errors    []error      // Members, *Error or any error
+	Strategy  CodeStrategy // How the code of the collection is computed
+	Aggregate Code         // Code used by the FixedCode strategy
+	Limit     int          // Maximum number of rendered members, 0 for no limit
+}
+
+// Aggregate errors like errors.Join.
+//
+// The nil errors are ignored, returns nil if every error is nil.
+func Join(errs ...error) error {
+	multi := &MultiError{}
+	multi.Add(errs...)
+	return multi.ErrorOrNil()
+}
+
+// Append errors, the nil errors (including nil *Error) are ignored
+func (multi *MultiError) Add(errs ...error) {
+	for _, err := range errs {
+		if converted, isError := err.(*Error); err == nil || (isError && converted == nil) {
+			continue
+		}
+
+		multi.Errors = append(multi.Errors, err)
+	}
+}
+
+// Number of members
+func (multi MultiError) Len() int {
+	return len(multi.Errors)
+}
+
+// The collection as error, nil if it is empty
+func (multi *MultiError) ErrorOrNil() error {
+	if multi == nil || len(multi.Errors) == 0 {
+		return nil
+	}
+
+	return multi
+}
+
+// Members of the collection
+func (multi MultiError) Unwrap() []error {
+	return multi.Errors
+}
+
+// Code of the collection computed with its Strategy.
+//
+// The errors which are not an Error have the UnknownError code.
+func (multi MultiError) Code() Code {
+	if multi.Strategy == FixedCode || len(multi.Errors) == 0 {
+		return multi.Aggregate
+	}
+
+	codes := make([]Code, 0, len(multi.Errors))
+	for _, err := range multi.Errors {
+		codes = append(codes, memberCode(err))
+	}
+
+	if multi.Strategy == FirstCode {
+		return codes[0]
+	}
+
+	result := codes[0]
+	for _, code := range codes[1:] {
+		if codeStatus(code) > codeStatus(result) {
+			result = code
+		}
+	}
+
+	return result
+}
+
+// Code of the first Error or MultiError of the member chain, in the errors.As order
+func memberCode(err error) Code {
+	if code, exists := chainCode(err); exists {
+		return code
+	}
+
+	return UnknownError
+}
+
+func chainCode(err error) (Code, bool) {
+	switch member := err.(type) {
+	case nil:
+		return Code{}, false
+	case *Error:
+		if member != nil {
+			return member.Code, true
+		}
+	case *MultiError:
+		if member != nil {
+			return member.Code(), true
+		}
+	}
+
+	switch wrapper := err.(type) {
+	case interface{ Unwrap() error }:
+		return chainCode(wrapper.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, cause := range wrapper.Unwrap() {
+			if code, exists := chainCode(cause); exists {
+				return code, true
+			}
+		}
+	}
+
+	return Code{}, false
+}
+
+// HTTP status of a code, 500 if the code is not registered
+func codeStatus(code Code) int {
+	if entry, exists := DefaultCatalog.LookupCode(code); exists && entry.HTTPStatus != 0 {
+		return entry.HTTPStatus
+	}
+
+	return 500
+}
+
+// Summary message of the collection
+func (multi MultiError) Message() string {
+	if len(multi.Errors) == 1 {
+		return "1 error occurred"
+	}
+
+	return fmt.Sprintf("%d errors occurred", len(multi.Errors))
+}
+
+// Same output as Error.Error depending of the GOPHERPANIC_FORMAT value
+func (multi MultiError) Error() string {
+	switch GopherpanicFormat {
+	case Custom:
+		return multi.Format(true, true)
+	case CustomWithTraces:
+		return multi.FormatWithTraces(true)
+	case GNUWithTraces:
+		return multi.FormatWithTraces(false)
+	default:
+		return multi.Format(false, true)
+	}
+}
+
+// Convert into string the collection and its numbered members without Traces.
+//
+//	Error: 4:failed to perform client api task:2 errors occurred
+//		1. user.go:12: Error: 4:failed to perform client api task:name is required
+//		2. user.go:15: Error: 4:failed to perform client api task:age must be positive
+func (multi MultiError) Format(custom bool, withInnerData bool) string {
+	return multi.format(custom, func(member error) string {
+		switch member := member.(type) {
+		case *Error:
+			return member.Format(custom, withInnerData)
+		case *MultiError:
+			return member.Format(custom, withInnerData)
+		default:
+			return formatForeignError(member, custom)
+		}
+	})
+}
+
+// Convert into string the collection and its numbered members with their Traces
+func (multi MultiError) FormatWithTraces(custom bool) string {
+	return multi.format(custom, func(member error) string {
+		switch member := member.(type) {
+		case *Error:
+			return member.FormatWithTraces(custom)
+		case *MultiError:
+			return member.FormatWithTraces(custom)
+		default:
+			return formatForeignError(member, custom)
+		}
+	})
+}
+
+func (multi MultiError) format(custom bool, formatMember func(error) string) string {
+	code := multi.Code()
+	var builder strings.Builder
+
+	if custom {
+		fmt.Fprintf(&builder, "code id: %d; description: %s\n\terror message: %s", code.ID, escapeText(code.Description), multi.Message())
+	} else {
+		fmt.Fprintf(&builder, "Error: %d:%s:%s", code.ID, escapeText(code.Description), multi.Message())
+	}
+
+	members, omitted := multi.rendered()
+	for index, member := range members {
+		lines := strings.Split(formatMember(member), "\n")
+		fmt.Fprintf(&builder, "\n\t%d. %s", index+1, strings.Join(lines, "\n\t"))
+	}
+
+	if omitted > 0 {
+		fmt.Fprintf(&builder, "\n\t... and %d more", omitted)
+	}
+
+	return builder.String()
+}
+
+// Members rendered according to the Limit and the number of omitted members
+func (multi MultiError) rendered() ([]error, int) {
+	if multi.Limit <= 0 || len(multi.Errors) <= multi.Limit {
+		return multi.Errors, 0
+	}
+
+	return multi.Errors[:multi.Limit], len(multi.Errors) - multi.Limit
+}
+
+func formatForeignError(err error, custom bool) string {
+	if custom {
+		return fmt.Sprintf("error message: %s", textMessage(err.Error()))
+	}
+
+	return fmt.Sprintf("Error: %s", textMessage(err.Error()))
+}
+
+type foreignErrorJSON struct {
+	Message string `json:"message"`
+}
+
+type multiErrorJSON struct {
+	Code    Code   `json:"code"`
+	Message string `json:"message"`
+	Errors  []any  `json:"errors"`
+	Omitted int    `json:"omitted,omitempty"`
+}
+
+// Convert into JSON with the rendered members, the errors which are not an Error only have a message
+func (multi MultiError) MarshalJSON() ([]byte, error) {
+	members, omitted := multi.rendered()
+	result := multiErrorJSON{
+		Code:    multi.Code(),
+		Message: multi.Message(),
+		Errors:  make([]any, 0, len(members)),
+		Omitted: omitted,
+	}
+
+	for _, member := range members {
+		switch member := member.(type) {
+		case *Error:
+			result.Errors = append(result.Errors, member.Redacted())
+		case *MultiError:
+			result.Errors = append(result.Errors, member)
+		default:
+			result.Errors = append(result.Errors, foreignErrorJSON{Message: redact(member.Error())})
+		}
+	}
+
+	return json.Marshal(result)
+}
+
+// Convert into JSON string (with or without indentation)
+func (multi MultiError) FormatJSON(indent bool) string {
+	return formatJSON(multi, indent)
+}
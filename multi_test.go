@@ -0,0 +1,155 @@
+package gopherpanic
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func multiErrorSample() MultiError {
+	return MultiError{
+		Errors: []error{
+			&Error{Code: ClientError, Message: "name is required", Position: Position{File: "user.go", Line: 12}},
+			&Error{Code: InternalError, Message: "cache unavailable", Position: Position{File: "cache.go", Line: 40}, Traces: []Trace{{Message: "dial failed", Position: Position{File: "cache.go", Line: 30}}}},
+			fmt.Errorf("age must be positive"),
+		},
+	}
+}
+
+func TestMultiErrorAdd(t *testing.T) {
+	var nilError *Error
+	multi := MultiError{}
+	multi.Add(nil, nilError, New(ClientError, "name is required"), fmt.Errorf("age must be positive"))
+
+	assert.Equal(t, 2, multi.Len())
+	assert.Len(t, multi.Unwrap(), 2)
+}
+
+func TestJoin(t *testing.T) {
+	var nilError *Error
+	assert.Nil(t, Join())
+	assert.Nil(t, Join(nil, nilError))
+
+	sentinel := fmt.Errorf("sentinel")
+	root := New(IOError, "disk full")
+	joined := Join(fmt.Errorf("wrapped: %w", sentinel), root)
+
+	var converted *Error
+	assert.ErrorIs(t, joined, sentinel)
+	assert.True(t, errors.As(joined, &converted))
+	assert.Equal(t, root, converted)
+}
+
+func TestMultiErrorCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields MultiError
+		want   Code
+	}{
+		{
+			name:   "OK - most severe",
+			fields: multiErrorSample(),
+			want:   InternalError,
+		},
+		{
+			name:   "OK - most severe keeps the first on equality",
+			fields: MultiError{Errors: []error{New(IOError, "disk full"), New(InternalError, "nil pointer")}},
+			want:   IOError,
+		},
+		{
+			name:   "OK - first",
+			fields: MultiError{Errors: multiErrorSample().Errors, Strategy: FirstCode},
+			want:   ClientError,
+		},
+		{
+			name:   "OK - fixed",
+			fields: MultiError{Errors: multiErrorSample().Errors, Strategy: FixedCode, Aggregate: TimeoutError},
+			want:   TimeoutError,
+		},
+		{
+			name:   "OK - wrapped members",
+			fields: MultiError{Errors: []error{fmt.Errorf("load: %w", New(ClientError, "name is required")), fmt.Errorf("save: %w", &MultiError{Errors: []error{New(TimeoutError, "query timeout")}})}, Strategy: FirstCode},
+			want:   ClientError,
+		},
+		{
+			name:   "OK - wrapped members severity",
+			fields: MultiError{Errors: []error{fmt.Errorf("load: %w", New(ClientError, "name is required")), fmt.Errorf("save: %w", New(InternalError, "nil pointer"))}},
+			want:   InternalError,
+		},
+		{
+			name:   "OK - foreign errors",
+			fields: MultiError{Errors: []error{fmt.Errorf("failure")}},
+			want:   UnknownError,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, testCase.fields.Code())
+		})
+	}
+}
+
+func TestMultiErrorFormat(t *testing.T) {
+	limited := multiErrorSample()
+	limited.Limit = 1
+
+	tests := []struct {
+		name   string
+		format func() string
+		want   string
+	}{
+		{
+			name:   "OK - GNU",
+			format: func() string { return multiErrorSample().Format(false, true) },
+			want: "Error: 3:failed to perform application task:3 errors occurred\n" +
+				"\t1. user.go:12: Error: 4:failed to perform client api task:name is required\n" +
+				"\t2. cache.go:40: Error: 3:failed to perform application task:cache unavailable\n" +
+				"\t3. Error: age must be positive",
+		},
+		{
+			name:   "OK - GNU with traces",
+			format: func() string { return multiErrorSample().FormatWithTraces(false) },
+			want: "Error: 3:failed to perform application task:3 errors occurred\n" +
+				"\t1. user.go:12: Error: 4:failed to perform client api task:name is required\n" +
+				"\t2. cache.go:40: Error: 3:failed to perform application task:cache unavailable\n" +
+				"\tcache.go:30: Error: dial failed\n" +
+				"\t3. Error: age must be positive",
+		},
+		{
+			name:   "OK - custom without inner data",
+			format: func() string { return multiErrorSample().Format(true, false) },
+			want: "code id: 3; description: failed to perform application task\n\terror message: 3 errors occurred\n" +
+				"\t1. code id: 4; description: failed to perform client api task\n\t\terror message: name is required\n" +
+				"\t2. code id: 3; description: failed to perform application task\n\t\terror message: cache unavailable\n" +
+				"\t3. error message: age must be positive",
+		},
+		{
+			name:   "OK - limit",
+			format: func() string { return limited.Format(false, false) },
+			want: "Error: 3:failed to perform application task:3 errors occurred\n" +
+				"\t1. Error: 4:failed to perform client api task:name is required\n" +
+				"\t... and 2 more",
+		},
+		{
+			name:   "OK - JSON",
+			format: func() string { return limited.FormatJSON(false) },
+			want:   `{"code":{"id":3,"description":"failed to perform application task"},"message":"3 errors occurred","errors":[{"code":{"id":4,"description":"failed to perform client api task"},"message":"name is required","position":{"file":"user.go","line":12}}],"omitted":2}`,
+		},
+		{
+			name: "OK - JSON foreign error",
+			format: func() string {
+				return MultiError{Errors: []error{fmt.Errorf("contact john@example.com")}}.FormatJSON(false)
+			},
+			want: `{"code":{"id":0,"description":"failed to perform task"},"message":"1 error occurred","errors":[{"message":"contact [REDACTED]"}]}`,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, testCase.format())
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package gopherpanic
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Client view of an Error.
+//
+// It follows the RFC 7807 problem details format and never contains the internal message,
+// the positions or the traces.
+type PublicError struct {
+	Type          string    `json:"type"`                     // Documentation URL of the code (about:blank if the code has none)
+	Title         string    `json:"title"`                    // Code description
+	Status        int       `json:"status,omitempty"`         // HTTP status of the code
+	Detail        string    `json:"detail,omitempty"`         // Public message
+	Code          ErrorKind `json:"code"`                     // Code ID
+	CorrelationID string    `json:"correlation_id,omitempty"` // Identifier shared with the logs
+	Instance      string    `json:"instance,omitempty"`       // Instance ID of the error
+
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"` // Field violations of a validation error
+}
+
+// Convert into the client view.
+//
+// The type and status come from the code entry of the DefaultCatalog, the detail is redacted
+// with the DefaultRedactor.
+func (err Error) Public() PublicError {
+	public := PublicError{
+		Type:          "about:blank",
+		Title:         err.Code.Description,
+		Detail:        redact(err.PublicMessage),
+		Code:          err.Code.ID,
+		CorrelationID: err.CorrelationID,
+		Instance:      err.InstanceID,
+		InvalidParams: err.Violations.InvalidParams(),
+	}
+
+	if entry, exists := DefaultCatalog.LookupCode(err.Code); exists {
+		public.Status = entry.HTTPStatus
+		if entry.DocsURL != "" {
+			public.Type = entry.DocsURL
+		}
+	}
+
+	return public
+}
+
+// Convert into string
+//
+// Error: 4:failed to perform client api task:the user does not exist (correlation id: 7f3a, instance: 01HV6Z3Q4M8N2P5R7T9W1X3Y5Z)
+//
+// The invalid parameters are listed after, one per line.
+func (public PublicError) Format() string {
+	public.Title = escapeText(public.Title)
+	public.Detail = textMessage(public.Detail)
+	public.CorrelationID = escapeText(public.CorrelationID)
+	public.Instance = escapeText(public.Instance)
+
+	result := fmt.Sprintf("Error: %d:%s", public.Code, public.Title)
+	if public.Detail != "" {
+		result += ":" + public.Detail
+	}
+
+	var references []string
+	if public.CorrelationID != "" {
+		references = append(references, "correlation id: "+public.CorrelationID)
+	}
+
+	if public.Instance != "" {
+		references = append(references, "instance: "+public.Instance)
+	}
+
+	if len(references) > 0 {
+		result += " (" + strings.Join(references, ", ") + ")"
+	}
+
+	for _, param := range public.InvalidParams {
+		result += fmt.Sprintf("\n\t%s: %s", escapeText(param.Name), textMessage(param.Reason))
+	}
+
+	return result
+}
+
+// Convert into JSON string (with or without indentation)
+func (public PublicError) FormatJSON(indent bool) string {
+	var data []byte
+
+	if indent {
+		data, _ = json.MarshalIndent(public, "", "\t")
+		return string(data)
+	}
+
+	data, _ = json.Marshal(public)
+	return string(data)
+}
@@ -0,0 +1,125 @@
+package gopherpanic
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorPublic(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields Error
+		want   PublicError
+	}{
+		{
+			name: "OK - builtin code",
+			fields: ErrorBuilder{}.
+				WithCode(ClientError).
+				WithMessage("user 42 not found in table users").
+				WithPublicMessage("the user does not exist").
+				WithCorrelationID("7f3a").
+				Build(),
+			want: PublicError{
+				Type:          "about:blank",
+				Title:         "failed to perform client api task",
+				Status:        400,
+				Detail:        "the user does not exist",
+				Code:          ClientError.ID,
+				CorrelationID: "7f3a",
+			},
+		},
+		{
+			name:   "OK - no public message",
+			fields: Error{Code: InternalError, Message: "nil pointer in cache"},
+			want: PublicError{
+				Type:   "about:blank",
+				Title:  "failed to perform application task",
+				Status: 500,
+				Code:   InternalError.ID,
+			},
+		},
+		{
+			name:   "OK - unregistered code",
+			fields: Error{Code: Code{ID: 4242, Description: "custom"}, PublicMessage: "try later"},
+			want: PublicError{
+				Type:   "about:blank",
+				Title:  "custom",
+				Detail: "try later",
+				Code:   4242,
+			},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, testCase.fields.Public())
+		})
+	}
+}
+
+func TestPublicErrorFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields PublicError
+		want   string
+	}{
+		{
+			name:   "OK",
+			fields: PublicError{Title: "failed to perform client api task", Detail: "the user does not exist", Code: 4, CorrelationID: "7f3a"},
+			want:   "Error: 4:failed to perform client api task:the user does not exist (correlation id: 7f3a)",
+		},
+		{
+			name:   "OK - title only",
+			fields: PublicError{Title: "failed to perform application task", Code: 3},
+			want:   "Error: 3:failed to perform application task",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, testCase.fields.Format())
+		})
+	}
+}
+
+func TestPublicErrorFormatJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields PublicError
+		args   bool
+		want   string
+	}{
+		{
+			name:   "OK",
+			fields: PublicError{Type: "about:blank", Title: "failed to perform application task", Status: 500, Code: 3, CorrelationID: "7f3a"},
+			args:   false,
+			want:   `{"type":"about:blank","title":"failed to perform application task","status":500,"code":3,"correlation_id":"7f3a"}`,
+		},
+		{
+			name:   "OK - indent",
+			fields: PublicError{Type: "about:blank", Title: "custom", Detail: "try later", Code: 4242},
+			args:   true,
+			want:   "{\n\t\"type\": \"about:blank\",\n\t\"title\": \"custom\",\n\t\"detail\": \"try later\",\n\t\"code\": 4242\n}",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, testCase.fields.FormatJSON(testCase.args))
+		})
+	}
+}
+
+func ExampleError_Public() {
+	err := ErrorBuilder{}.
+		WithCode(ClientError).
+		WithMessage("user 42 not found in table users").
+		WithPublicMessage("the user does not exist").
+		WithCorrelationID("7f3a").
+		Build()
+
+	fmt.Println(err.Public().FormatJSON(false))
+	// Output: {"type":"about:blank","title":"failed to perform client api task","status":400,"detail":"the user does not exist","code":4,"correlation_id":"7f3a"}
+}
@@ -0,0 +1,213 @@
+package gopherpanic
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Text which replaces the redacted data
+const Redacted = "[REDACTED]"
+
+// Redactor applied by every format (Format, FormatWithTraces, FormatJSON and the public view).
+//
+// It contains the builtin rules, set it to nil to disable the redaction.
+var DefaultRedactor *Redactor = NewRedactor(BuiltinRedactionRules()...)
+
+// Rule which replaces sensitive data of a text
+type RedactionRule struct {
+	Name    string              // Identifier of the rule
+	Pattern *regexp.Regexp      // Matches the sensitive data (optional if Replace is set)
+	Replace func(string) string // Replaces a match, or the whole text if Pattern is nil (default: Redacted)
+}
+
+// Ordered collection of redaction rules
+type Redactor struct {
+	mutex sync.RWMutex
+	rules []RedactionRule
+}
+
+// Value which is never rendered, neither in the messages nor in JSON
+type SensitiveValue struct {
+	value any
+}
+
+// Mark a template argument as sensitive
+//
+// NewTemplate(ClientError, "invalid password {password}", Args{"password": Sensitive(password)})
+func Sensitive(value any) SensitiveValue {
+	return SensitiveValue{value: value}
+}
+
+// Original value
+func (sensitive SensitiveValue) Reveal() any {
+	return sensitive.value
+}
+
+func (sensitive SensitiveValue) String() string {
+	return Redacted
+}
+
+func (sensitive SensitiveValue) GoString() string {
+	return Redacted
+}
+
+func (sensitive SensitiveValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Redacted)
+}
+
+// Rules which detect bearer tokens, API keys, e-mail addresses and credit card numbers
+func BuiltinRedactionRules() []RedactionRule {
+	return []RedactionRule{
+		{
+			Name:    "bearer",
+			Pattern: regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9\-._~+/]+=*`),
+			Replace: func(match string) string {
+				scheme, _, _ := strings.Cut(match, " ")
+				return scheme + " " + Redacted
+			},
+		},
+		{
+			Name:    "secret",
+			Pattern: regexp.MustCompile(`(?i)\b(api[_-]?key|access[_-]?token|token|secret|password|passwd)(["']?\s*[:=]\s*["']?)[^\s"'&,;]+`),
+			Replace: func(match string) string {
+				index := strings.IndexAny(match, ":=")
+				separator := strings.TrimLeft(match[index+1:], " \t\"'")
+				return match[:len(match)-len(separator)] + Redacted
+			},
+		},
+		{
+			Name:    "apikey",
+			Pattern: regexp.MustCompile(`\b(?:(?:sk|pk|rk)_(?:live|test)_[A-Za-z0-9]{10,}|gh[pousr]_[A-Za-z0-9]{36,}|AKIA[0-9A-Z]{16}|xox[abprs]-[A-Za-z0-9-]{10,})\b`),
+		},
+		{
+			Name:    "email",
+			Pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`),
+		},
+		{
+			Name:    "creditcard",
+			Pattern: regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`),
+			Replace: func(match string) string {
+				if !luhn(match) {
+					return match
+				}
+				return Redacted
+			},
+		},
+	}
+}
+
+// Create a new Redactor which applies the rules in order
+func NewRedactor(rules ...RedactionRule) *Redactor {
+	return &Redactor{rules: rules}
+}
+
+// Add rules applied after the existing ones
+func (redactor *Redactor) Add(rules ...RedactionRule) {
+	redactor.mutex.Lock()
+	defer redactor.mutex.Unlock()
+
+	redactor.rules = append(redactor.rules, rules...)
+}
+
+// Add a rule which replaces the matches of a regular expression by Redacted
+func (redactor *Redactor) AddPattern(name string, pattern string) error {
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return New(ClientError, fmt.Sprintf("invalid redaction pattern %s: %s", name, err))
+	}
+
+	redactor.Add(RedactionRule{Name: name, Pattern: compiled})
+	return nil
+}
+
+// Add a rule which rewrites the whole text
+func (redactor *Redactor) AddFunc(name string, replace func(string) string) {
+	redactor.Add(RedactionRule{Name: name, Replace: replace})
+}
+
+// Apply the rules on a text
+func (redactor *Redactor) Redact(text string) string {
+	if redactor == nil || text == "" {
+		return text
+	}
+
+	redactor.mutex.RLock()
+	defer redactor.mutex.RUnlock()
+
+	for _, rule := range redactor.rules {
+		switch {
+		case rule.Pattern != nil && rule.Replace != nil:
+			text = rule.Pattern.ReplaceAllStringFunc(text, rule.Replace)
+		case rule.Pattern != nil:
+			text = rule.Pattern.ReplaceAllLiteralString(text, Redacted)
+		case rule.Replace != nil:
+			text = rule.Replace(text)
+		}
+	}
+
+	return text
+}
+
+// Copy of the error with the DefaultRedactor applied on the messages, the traces and the arguments
+func (err Error) Redacted() Error {
+	redacted := err
+	redacted.Message = redact(err.Message)
+	redacted.PublicMessage = redact(err.PublicMessage)
+	redacted.Violations = err.Violations.Redacted()
+
+	if len(err.Traces) > 0 {
+		redacted.Traces = make([]Trace, len(err.Traces))
+		for index, trace := range err.Traces {
+			redacted.Traces[index] = trace.Redacted()
+		}
+	}
+
+	if len(err.Args) > 0 {
+		redacted.Args = make(Args, len(err.Args))
+		for name, value := range err.Args {
+			if text, isString := value.(string); isString {
+				value = redact(text)
+			}
+			redacted.Args[name] = value
+		}
+	}
+
+	return redacted
+}
+
+// Copy of the trace with the DefaultRedactor applied on the message
+func (trace Trace) Redacted() Trace {
+	trace.Message = redact(trace.Message)
+	return trace
+}
+
+func redact(text string) string {
+	return DefaultRedactor.Redact(text)
+}
+
+// Check the Luhn checksum of a number which can contain spaces and dashes
+func luhn(number string) bool {
+	sum := 0
+	double := false
+	for index := len(number) - 1; index >= 0; index-- {
+		if number[index] < '0' || number[index] > '9' {
+			continue
+		}
+
+		digit := int(number[index] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+
+		sum += digit
+		double = !double
+	}
+
+	return sum%10 == 0
+}
@@ -0,0 +1,279 @@
+package gopherpanic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Retry semantic of an Error
+type RetryClass uint
+
+const (
+	RetryFromCode RetryClass = iota // Retryable if the catalog entry of the code is Retryable
+	Retryable                       // The task can be retried
+	Permanent                       // The task fails again if it is retried
+)
+
+var retryClassNames = map[RetryClass]string{
+	RetryFromCode: "code",
+	Retryable:     "retryable",
+	Permanent:     "permanent",
+}
+
+func (retry RetryClass) String() string {
+	if name, exists := retryClassNames[retry]; exists {
+		return name
+	}
+
+	return fmt.Sprintf("retry(%d)", uint(retry))
+}
+
+func (retry RetryClass) MarshalText() ([]byte, error) {
+	return []byte(retry.String()), nil
+}
+
+func (retry *RetryClass) UnmarshalText(data []byte) error {
+	for class, name := range retryClassNames {
+		if string(data) == name {
+			*retry = class
+			return nil
+		}
+	}
+
+	return New(ClientError, fmt.Sprintf("unknown retry class %q", data))
+}
+
+// Report if the failed task is worth retrying.
+//
+// The cause chain is walked depth first: the first Error with an explicit Retry, context.Canceled
+// (permanent) or an error with a true Temporary() or Timeout() method decides. Otherwise the error
+// is retryable if one of the codes of the chain is Retryable in the DefaultCatalog.
+func IsRetryable(err error) bool {
+	class, retryableCode := classifyRetry(err)
+	if class == RetryFromCode {
+		return retryableCode
+	}
+
+	return class == Retryable
+}
+
+func classifyRetry(err error) (RetryClass, bool) {
+	if err == nil {
+		return RetryFromCode, false
+	}
+
+	retryableCode := false
+	switch converted := err.(type) {
+	case *Error:
+		if converted == nil {
+			return RetryFromCode, false
+		}
+		if converted.Retry != RetryFromCode {
+			return converted.Retry, false
+		}
+		retryableCode = codeRetryable(converted.Code)
+	case interface{ Timeout() bool }:
+		if converted.Timeout() {
+			return Retryable, false
+		}
+	}
+
+	if err == context.Canceled {
+		return Permanent, false
+	}
+
+	if temporary, isTemporary := err.(interface{ Temporary() bool }); isTemporary && temporary.Temporary() {
+		return Retryable, false
+	}
+
+	var causes []error
+	switch wrapper := err.(type) {
+	case interface{ Unwrap() error }:
+		causes = []error{wrapper.Unwrap()}
+	case interface{ Unwrap() []error }:
+		causes = wrapper.Unwrap()
+	}
+
+	for _, cause := range causes {
+		class, retryableCause := classifyRetry(cause)
+		if class != RetryFromCode {
+			return class, false
+		}
+		retryableCode = retryableCode || retryableCause
+	}
+
+	return RetryFromCode, retryableCode
+}
+
+func codeRetryable(code Code) bool {
+	entry, exists := DefaultCatalog.LookupCode(code)
+	return exists && entry.Retryable
+}
+
+// Largest RetryAfter of the Errors of the cause chain, false if there is none
+func RetryAfter(err error) (time.Duration, bool) {
+	if err == nil {
+		return 0, false
+	}
+
+	delay := time.Duration(0)
+	if converted, isError := err.(*Error); isError && converted != nil {
+		delay = converted.RetryAfter
+	}
+
+	var causes []error
+	switch wrapper := err.(type) {
+	case interface{ Unwrap() error }:
+		causes = []error{wrapper.Unwrap()}
+	case interface{ Unwrap() []error }:
+		causes = wrapper.Unwrap()
+	}
+
+	for _, cause := range causes {
+		if causeDelay, exists := RetryAfter(cause); exists && causeDelay > delay {
+			delay = causeDelay
+		}
+	}
+
+	return delay, delay > 0
+}
+
+// Configuration of Retry, the zero values use the defaults
+type RetryOptions struct {
+	Attempts     int                                        // Maximum number of attempts (default: 3)
+	InitialDelay time.Duration                              // Delay after the first attempt (default: 100ms)
+	MaxDelay     time.Duration                              // Upper bound of the delay, 0 for no bound
+	Multiplier   float64                                    // Growth of the delay between two attempts (default: 2)
+	Jitter       float64                                    // Random variation of the delay, between 0 and 1 (0.2: +/- 20%)
+	Retryable    func(err error) bool                       // Classification of the errors (default: IsRetryable)
+	After        func(delay time.Duration) <-chan time.Time // Clock used to wait between the attempts (default: time.After)
+	Random       func() float64                             // Source of the jitter in [0, 1) (default: math/rand)
+}
+
+func (options RetryOptions) withDefaults() RetryOptions {
+	if options.Attempts <= 0 {
+		options.Attempts = 3
+	}
+	if options.InitialDelay <= 0 {
+		options.InitialDelay = 100 * time.Millisecond
+	}
+	if options.Multiplier <= 0 {
+		options.Multiplier = 2
+	}
+	if options.Retryable == nil {
+		options.Retryable = IsRetryable
+	}
+	if options.After == nil {
+		options.After = time.After
+	}
+	if options.Random == nil {
+		options.Random = rand.Float64
+	}
+
+	return options
+}
+
+// Delay after an attempt (starting at 1) with exponential backoff and jitter
+func (options RetryOptions) delay(attempt int) time.Duration {
+	delay := float64(options.InitialDelay) * math.Pow(options.Multiplier, float64(attempt-1))
+	delay += delay * options.Jitter * (2*options.Random() - 1)
+
+	if options.MaxDelay > 0 && delay > float64(options.MaxDelay) {
+		return options.MaxDelay
+	}
+
+	if delay >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	return time.Duration(delay)
+}
+
+// Run the task until it succeeds, returns a permanent error, the attempts are exhausted or the context is done.
+//
+// The delay between two attempts grows exponentially and is at least the RetryAfter of the error.
+// The returned Error has the code of the last error, a trace per failed attempt and every attempt
+// error (and the context error) as causes.
+func Retry(ctx context.Context, options RetryOptions, task func(ctx context.Context) error) error {
+	options = options.withDefaults()
+	position := Position{}.spawn(2)
+
+	var causes []error
+	var traces []Trace
+	reason := "attempts exhausted"
+
+	for attempt := 1; ; attempt++ {
+		err := task(ctx)
+		if converted, isError := err.(*Error); err == nil || (isError && converted == nil) {
+			return nil
+		}
+
+		causes = append(causes, err)
+		traces = append(traces, attemptTrace(attempt, err, position))
+
+		if !options.Retryable(err) {
+			reason = "permanent error"
+			break
+		}
+
+		if attempt >= options.Attempts {
+			break
+		}
+
+		delay := options.delay(attempt)
+		if retryAfter, exists := RetryAfter(err); exists && retryAfter > delay {
+			delay = retryAfter
+		}
+
+		select {
+		case <-ctx.Done():
+			causes = append(causes, context.Cause(ctx))
+			reason = "context done"
+		case <-options.After(delay):
+			continue
+		}
+
+		break
+	}
+
+	code := UnknownError
+	var last *Error
+	if errors.As(causes[len(traces)-1], &last) {
+		code = last.Code
+	}
+
+	attempts := fmt.Sprintf("%d attempts", len(traces))
+	if len(traces) == 1 {
+		attempts = "1 attempt"
+	}
+
+	return &Error{
+		Code:        code,
+		Message:     fmt.Sprintf("task failed after %s: %s", attempts, reason),
+		Position:    position,
+		Traces:      traces,
+		InstanceID:  inheritInstanceID(causes),
+		Time:        newTimestamp(),
+		Environment: newEnvironment(),
+		causes:      causes,
+	}
+}
+
+// Trace of a failed attempt at the position of the error, or of the Retry call for the other errors
+func attemptTrace(attempt int, err error, position Position) Trace {
+	message := err.Error()
+	if converted, isError := err.(*Error); isError {
+		message = converted.Message
+		position = converted.Position
+	}
+
+	return Trace{
+		Message:  fmt.Sprintf("attempt %d: %s", attempt, message),
+		Position: position,
+		Time:     newTimestamp(),
+	}
+}
@@ -0,0 +1,214 @@
+package gopherpanic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type temporaryError struct {
+	temporary bool
+}
+
+func (err temporaryError) Error() string {
+	return "temporary failure"
+}
+
+func (err temporaryError) Temporary() bool {
+	return err.temporary
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		args error
+		want bool
+	}{
+		{
+			name: "OK - nil",
+			args: nil,
+			want: false,
+		},
+		{
+			name: "OK - retryable code",
+			args: New(NetworkError, "connection reset"),
+			want: true,
+		},
+		{
+			name: "OK - permanent code",
+			args: New(ClientError, "name is required"),
+			want: false,
+		},
+		{
+			name: "OK - retryable override",
+			args: &Error{Code: ClientError, Message: "too many requests", Retry: Retryable},
+			want: true,
+		},
+		{
+			name: "OK - permanent override",
+			args: &Error{Code: NetworkError, Message: "unknown host", Retry: Permanent},
+			want: false,
+		},
+		{
+			name: "OK - retryable cause",
+			args: Newf(InternalError, "cannot load user: %w", New(TimeoutError, "query timeout")),
+			want: true,
+		},
+		{
+			name: "OK - retryable wrapped error",
+			args: Wrap(InternalError, "cannot fetch user", New(NetworkError, "dial")),
+			want: true,
+		},
+		{
+			name: "OK - permanent cause",
+			args: Newf(NetworkError, "cannot load user: %w", &Error{Code: ClientError, Retry: Permanent}),
+			want: false,
+		},
+		{
+			name: "OK - timeout",
+			args: fmt.Errorf("dial: %w", &net.DNSError{Err: "timeout", IsTimeout: true}),
+			want: true,
+		},
+		{
+			name: "OK - deadline exceeded",
+			args: fmt.Errorf("query: %w", context.DeadlineExceeded),
+			want: true,
+		},
+		{
+			name: "OK - canceled",
+			args: Newf(NetworkError, "query: %w", context.Canceled),
+			want: false,
+		},
+		{
+			name: "OK - temporary",
+			args: Join(os.ErrNotExist, temporaryError{temporary: true}),
+			want: true,
+		},
+		{
+			name: "OK - not temporary",
+			args: temporaryError{temporary: false},
+			want: false,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, IsRetryable(testCase.args))
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	limited := ErrorBuilder{}.WithCode(ClientError).WithRetryAfter(time.Second).Build()
+	delay, exists := RetryAfter(Newf(InternalError, "cannot load: %w", &limited))
+	assert.True(t, exists)
+	assert.Equal(t, time.Second, delay)
+
+	delay, exists = RetryAfter(Wrap(InternalError, "cannot load", &limited))
+	assert.True(t, exists)
+	assert.Equal(t, time.Second, delay)
+
+	_, exists = RetryAfter(New(NetworkError, "connection reset"))
+	assert.False(t, exists)
+}
+
+func TestRetryClassJSON(t *testing.T) {
+	err := ErrorBuilder{}.WithCode(ClientError).WithRetryAfter(2 * time.Second).Build()
+	assert.Contains(t, err.FormatJSON(false), `"retry":"retryable","retry_after":2000000000`)
+
+	var retry RetryClass
+	assert.Nil(t, retry.UnmarshalText([]byte("permanent")))
+	assert.Equal(t, Permanent, retry)
+	assert.NotNil(t, retry.UnmarshalText([]byte("never")))
+}
+
+func retryTestOptions(delays *[]time.Duration) RetryOptions {
+	return RetryOptions{
+		Attempts:     4,
+		InitialDelay: 100 * time.Millisecond,
+		MaxDelay:     300 * time.Millisecond,
+		Jitter:       0.5,
+		Random:       func() float64 { return 0.5 },
+		After: func(delay time.Duration) <-chan time.Time {
+			*delays = append(*delays, delay)
+			channel := make(chan time.Time, 1)
+			channel <- time.Time{}
+			return channel
+		},
+	}
+}
+
+func TestRetry(t *testing.T) {
+	var delays []time.Duration
+	attempts := 0
+	err := Retry(context.Background(), retryTestOptions(&delays), func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return New(NetworkError, "connection reset")
+		}
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, delays)
+}
+
+func TestRetryExhausted(t *testing.T) {
+	var delays []time.Duration
+	sentinel := New(TimeoutError, "query timeout")
+	err := Retry(context.Background(), retryTestOptions(&delays), func(ctx context.Context) error { return sentinel })
+
+	var converted *Error
+	assert.True(t, errors.As(err, &converted))
+	assert.Equal(t, TimeoutError, converted.Code)
+	assert.Equal(t, "task failed after 4 attempts: attempts exhausted", converted.Message)
+	assert.Len(t, converted.Traces, 4)
+	assert.Equal(t, "attempt 4: query timeout", converted.Traces[3].Message)
+	assert.ErrorIs(t, err, sentinel)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}, delays)
+}
+
+func TestRetryPermanent(t *testing.T) {
+	var delays []time.Duration
+	attempts := 0
+	err := Retry(context.Background(), retryTestOptions(&delays), func(ctx context.Context) error {
+		attempts++
+		if attempts == 1 {
+			limited := ErrorBuilder{}.WithCode(ClientError).WithMessage("rate limited").WithRetryAfter(time.Minute).Build()
+			return &limited
+		}
+		return fmt.Errorf("invalid token")
+	})
+
+	var converted *Error
+	assert.True(t, errors.As(err, &converted))
+	assert.Equal(t, UnknownError, converted.Code)
+	assert.Equal(t, "task failed after 2 attempts: permanent error", converted.Message)
+	assert.Equal(t, "attempt 2: invalid token", converted.Traces[1].Message)
+	assert.Equal(t, []time.Duration{time.Minute}, delays)
+}
+
+func TestRetryCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	options := RetryOptions{
+		After: func(delay time.Duration) <-chan time.Time {
+			cancel()
+			return nil
+		},
+	}
+
+	err := Retry(ctx, options, func(ctx context.Context) error { return New(NetworkError, "connection reset") })
+
+	var converted *Error
+	assert.True(t, errors.As(err, &converted))
+	assert.Equal(t, NetworkError, converted.Code)
+	assert.Equal(t, "task failed after 1 attempt: context done", converted.Message)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,221 @@
+package gopherpanic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Importance of a diagnostic.
+//
+// The zero value is SeverityError, so the errors created without severity keep their behavior.
+type Severity uint
+
+const (
+	SeverityError   Severity = iota // Failure of the task (Error:)
+	SeverityFatal                   // Failure which stops the program (Fatal:)
+	SeverityWarning                 // Suspicious input which does not stop the task (warning:)
+	SeverityInfo                    // Additional information (note:)
+	SeverityHint                    // Suggestion to fix another diagnostic (hint:)
+)
+
+var severityNames = map[Severity]string{
+	SeverityError:   "error",
+	SeverityFatal:   "fatal",
+	SeverityWarning: "warning",
+	SeverityInfo:    "info",
+	SeverityHint:    "hint",
+}
+
+// Name used by JSON and the custom format
+func (severity Severity) String() string {
+	if name, exists := severityNames[severity]; exists {
+		return name
+	}
+
+	return fmt.Sprintf("severity(%d)", uint(severity))
+}
+
+// Parse a severity name (error, fatal, warning, info or hint)
+func ParseSeverity(name string) (Severity, error) {
+	for severity, severityName := range severityNames {
+		if strings.EqualFold(name, severityName) {
+			return severity, nil
+		}
+	}
+
+	return SeverityError, New(ClientError, fmt.Sprintf("unknown severity %q", name))
+}
+
+func (severity Severity) MarshalText() ([]byte, error) {
+	return []byte(severity.String()), nil
+}
+
+func (severity *Severity) UnmarshalText(data []byte) error {
+	parsed, err := ParseSeverity(string(data))
+	if err != nil {
+		return err
+	}
+
+	*severity = parsed
+	return nil
+}
+
+// The diagnostic makes the task fail (SeverityError or SeverityFatal)
+func (severity Severity) Blocking() bool {
+	return severity == SeverityError || severity == SeverityFatal
+}
+
+// GNU label of the severity
+func (severity Severity) label() string {
+	switch severity {
+	case SeverityFatal:
+		return "Fatal:"
+	case SeverityWarning:
+		return "warning:"
+	case SeverityInfo:
+		return "note:"
+	case SeverityHint:
+		return "hint:"
+	default:
+		return "Error:"
+	}
+}
+
+// Custom format suffix of the code, empty for SeverityError
+func (severity Severity) formatCustom() string {
+	if severity == SeverityError {
+		return ""
+	}
+
+	return "; severity: " + severity.String()
+}
+
+func (severity Severity) color() string {
+	switch severity {
+	case SeverityWarning:
+		return ansiYellow
+	case SeverityInfo, SeverityHint:
+		return ansiCyan
+	default:
+		return ansiRed
+	}
+}
+
+// Collector of the diagnostics reported by a task (config loader, linter, ...).
+//
+// The warnings do not make the task fail unless WarningsAsErrors is enabled (like -Werror).
+type Diagnostics struct {
+	Items            []*Error // Reported diagnostics in order
+	WarningsAsErrors bool     // Promote the warnings to errors
+}
+
+// Append diagnostics, the nil diagnostics are ignored
+func (diagnostics *Diagnostics) Report(items ...*Error) {
+	for _, item := range items {
+		if item != nil {
+			diagnostics.Items = append(diagnostics.Items, item)
+		}
+	}
+}
+
+// Report an error at the current spawn position
+func (diagnostics *Diagnostics) Error(code Code, message string) {
+	diagnostics.report(SeverityError, code, message)
+}
+
+// Report a warning at the current spawn position
+func (diagnostics *Diagnostics) Warn(code Code, message string) {
+	diagnostics.report(SeverityWarning, code, message)
+}
+
+// Report a note at the current spawn position
+func (diagnostics *Diagnostics) Note(code Code, message string) {
+	diagnostics.report(SeverityInfo, code, message)
+}
+
+// Report a hint at the current spawn position
+func (diagnostics *Diagnostics) Hint(code Code, message string) {
+	diagnostics.report(SeverityHint, code, message)
+}
+
+func (diagnostics *Diagnostics) report(severity Severity, code Code, message string) {
+	diagnostics.Items = append(diagnostics.Items, &Error{
+		Code:        code,
+		Message:     message,
+		Position:    Position{}.spawn(3),
+		Severity:    severity,
+		InstanceID:  newInstanceID(),
+		Time:        newTimestamp(),
+		Environment: newEnvironment(),
+	})
+}
+
+// Diagnostics which make the task fail, the promoted warnings have the SeverityError severity
+func (diagnostics Diagnostics) Errors() []*Error {
+	var errors []*Error
+	for _, item := range diagnostics.Items {
+		switch {
+		case item.Severity.Blocking():
+			errors = append(errors, item)
+		case item.Severity == SeverityWarning && diagnostics.WarningsAsErrors:
+			promoted := *item
+			promoted.Severity = SeverityError
+			errors = append(errors, &promoted)
+		}
+	}
+
+	return errors
+}
+
+// Diagnostics which do not make the task fail
+func (diagnostics Diagnostics) Warnings() []*Error {
+	var warnings []*Error
+	for _, item := range diagnostics.Items {
+		if item.Severity.Blocking() || (item.Severity == SeverityWarning && diagnostics.WarningsAsErrors) {
+			continue
+		}
+
+		warnings = append(warnings, item)
+	}
+
+	return warnings
+}
+
+// At least one diagnostic makes the task fail
+func (diagnostics Diagnostics) HasErrors() bool {
+	return len(diagnostics.Errors()) > 0
+}
+
+// Result of the task: the non blocking diagnostics and the task error aggregated with the blocking
+// diagnostics into a *MultiError (nil if there is no error).
+//
+//	config, err := load(path, &diagnostics)
+//	warnings, err := diagnostics.Result(err)
+func (diagnostics Diagnostics) Result(err error) ([]*Error, error) {
+	multi := &MultiError{}
+	for _, item := range diagnostics.Errors() {
+		multi.Add(item)
+	}
+	multi.Add(err)
+
+	return diagnostics.Warnings(), multi.ErrorOrNil()
+}
+
+// Convert into string every diagnostic in GNU format, one per line
+//
+//	config.go:12: warning: 4:failed to perform client api task:unknown key "colour"
+//	config.go:12: hint: 4:failed to perform client api task:did you mean "color"?
+func (diagnostics Diagnostics) Format() string {
+	lines := make([]string, 0, len(diagnostics.Items))
+	for _, item := range diagnostics.Items {
+		if item.Severity == SeverityWarning && diagnostics.WarningsAsErrors {
+			promoted := *item
+			promoted.Severity = SeverityError
+			item = &promoted
+		}
+
+		lines = append(lines, item.Format(false, true))
+	}
+
+	return strings.Join(lines, "\n")
+}
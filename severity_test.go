@@ -0,0 +1,158 @@
+package gopherpanic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeverityFormat(t *testing.T) {
+	type args struct {
+		custom        bool
+		withInnerData bool
+	}
+
+	tests := []struct {
+		name   string
+		fields Error
+		args   args
+		want   string
+	}{
+		{
+			name:   "OK - error",
+			fields: Error{Code: ClientError, Message: "unknown key", Position: Position{File: "config.go", Line: 12}},
+			args:   args{custom: false, withInnerData: true},
+			want:   "config.go:12: Error: 4:failed to perform client api task:unknown key",
+		},
+		{
+			name:   "OK - fatal",
+			fields: Error{Code: ClientError, Message: "unknown key", Severity: SeverityFatal},
+			args:   args{custom: false, withInnerData: false},
+			want:   "Fatal: 4:failed to perform client api task:unknown key",
+		},
+		{
+			name:   "OK - warning",
+			fields: Error{Code: ClientError, Message: "unknown key", Position: Position{File: "config.go", Line: 12}, Severity: SeverityWarning},
+			args:   args{custom: false, withInnerData: true},
+			want:   "config.go:12: warning: 4:failed to perform client api task:unknown key",
+		},
+		{
+			name:   "OK - info",
+			fields: Error{Code: ClientError, Message: "defined here", Severity: SeverityInfo},
+			args:   args{custom: false, withInnerData: false},
+			want:   "note: 4:failed to perform client api task:defined here",
+		},
+		{
+			name:   "OK - hint",
+			fields: Error{Code: ClientError, Message: "did you mean color?", Severity: SeverityHint},
+			args:   args{custom: false, withInnerData: false},
+			want:   "hint: 4:failed to perform client api task:did you mean color?",
+		},
+		{
+			name:   "OK - custom warning",
+			fields: Error{Code: ClientError, Message: "unknown key", Severity: SeverityWarning},
+			args:   args{custom: true, withInnerData: false},
+			want:   "code id: 4; description: failed to perform client api task; severity: warning\n\terror message: unknown key",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, testCase.fields.Format(testCase.args.custom, testCase.args.withInnerData))
+		})
+	}
+}
+
+func TestSeverityJSON(t *testing.T) {
+	data, err := json.Marshal(Error{Code: ClientError, Message: "unknown key", Severity: SeverityWarning})
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"severity":"warning"`)
+
+	data, err = json.Marshal(Error{Code: ClientError, Message: "unknown key"})
+	assert.Nil(t, err)
+	assert.NotContains(t, string(data), "severity")
+
+	var decoded Error
+	assert.Nil(t, json.Unmarshal([]byte(`{"severity":"hint"}`), &decoded))
+	assert.Equal(t, SeverityHint, decoded.Severity)
+	assert.NotNil(t, json.Unmarshal([]byte(`{"severity":"critical"}`), &decoded))
+}
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		want    Severity
+		wantErr bool
+	}{
+		{name: "OK - warning", args: "WARNING", want: SeverityWarning},
+		{name: "OK - fatal", args: "fatal", want: SeverityFatal},
+		{name: "KO - unknown", args: "critical", want: SeverityError, wantErr: true},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := ParseSeverity(testCase.args)
+			assert.Equal(t, testCase.want, got)
+			assert.Equal(t, testCase.wantErr, err != nil)
+		})
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	diagnostics := Diagnostics{}
+	diagnostics.Warn(ClientError, "unknown key colour")
+	diagnostics.Hint(ClientError, "did you mean color?")
+	diagnostics.Report(nil)
+
+	assert.Equal(t, "severity_test.go", filepath.Base(diagnostics.Items[0].Position.File))
+	assert.Equal(t, 108, diagnostics.Items[0].Position.Line)
+	assert.False(t, diagnostics.HasErrors())
+
+	taskError := fmt.Errorf("config not found")
+	warnings, err := diagnostics.Result(nil)
+	assert.Len(t, warnings, 2)
+	assert.Nil(t, err)
+
+	warnings, err = diagnostics.Result(taskError)
+	assert.Len(t, warnings, 2)
+	assert.ErrorIs(t, err, taskError)
+
+	diagnostics.Error(ClientError, "missing key name")
+	warnings, err = diagnostics.Result(nil)
+	assert.Len(t, warnings, 2)
+
+	var multi *MultiError
+	assert.True(t, errors.As(err, &multi))
+	assert.Equal(t, 1, multi.Len())
+}
+
+func TestDiagnosticsWarningsAsErrors(t *testing.T) {
+	diagnostics := Diagnostics{
+		Items: []*Error{
+			{Code: ClientError, Message: "unknown key colour", Position: Position{File: "config.go", Line: 3}, Severity: SeverityWarning},
+			{Code: ClientError, Message: "did you mean color?", Position: Position{File: "config.go", Line: 3}, Severity: SeverityHint},
+		},
+		WarningsAsErrors: true,
+	}
+
+	warnings, err := diagnostics.Result(nil)
+	assert.Len(t, warnings, 1)
+	assert.Equal(t, SeverityHint, warnings[0].Severity)
+	assert.True(t, diagnostics.HasErrors())
+
+	var promoted *Error
+	assert.True(t, errors.As(err, &promoted))
+	assert.Equal(t, SeverityError, promoted.Severity)
+	assert.Equal(t, SeverityWarning, diagnostics.Items[0].Severity)
+
+	assert.Equal(t,
+		"config.go:3: Error: 4:failed to perform client api task:unknown key colour\n"+
+			"config.go:3: hint: 4:failed to perform client api task:did you mean color?",
+		diagnostics.Format(),
+	)
+}
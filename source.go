@@ -0,0 +1,160 @@
+package gopherpanic
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime/debug"
+	"strings"
+
+	"github.com/ulphidius/iterago"
+)
+
+// Source browser URL templates.
+//
+// Placeholders: {repository} (module path without its major version suffix), {module}, {revision}, {file} and {line}.
+const (
+	GitHubSourceURL = "https://{repository}/blob/{revision}/{file}#L{line}"
+	GitLabSourceURL = "https://{repository}/-/blob/{revision}/{file}#L{line}"
+	GiteaSourceURL  = "https://{repository}/src/commit/{revision}/{file}#L{line}"
+)
+
+// URL template of the source links of the main module positions, empty to disable the links.
+//
+// Initialized from GOPHERPANIC_SOURCE_URL (a template or github, gitlab, gitea).
+var GopherpanicSourceURL string = ""
+
+// VCS revision used by the source links.
+//
+// Initialized from GOPHERPANIC_REVISION or from the vcs.revision build setting.
+var GopherpanicRevision string = ""
+
+var majorVersionSuffix = regexp.MustCompile(`/v[0-9]+$`)
+
+func init() {
+	switch source := os.Getenv("GOPHERPANIC_SOURCE_URL"); strings.ToLower(source) {
+	case "github":
+		GopherpanicSourceURL = GitHubSourceURL
+	case "gitlab":
+		GopherpanicSourceURL = GitLabSourceURL
+	case "gitea":
+		GopherpanicSourceURL = GiteaSourceURL
+	default:
+		GopherpanicSourceURL = source
+	}
+
+	if info, exists := debug.ReadBuildInfo(); exists {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				GopherpanicRevision = setting.Value
+			}
+		}
+	}
+
+	if revision := os.Getenv("GOPHERPANIC_REVISION"); revision != "" {
+		GopherpanicRevision = revision
+	}
+}
+
+// Link to the position in the source browser at GopherpanicRevision.
+//
+// Empty if the links are disabled, if the revision is unknown or if the position is outside of the main module.
+func (position Position) SourceURL() string {
+	if GopherpanicSourceURL == "" || GopherpanicRevision == "" || mainModulePath == "" {
+		return ""
+	}
+
+	if position.Package != mainModulePath && !strings.HasPrefix(position.Package, mainModulePath+"/") {
+		return ""
+	}
+
+	file, inModule := moduleFile(position.File)
+	if !inModule {
+		return ""
+	}
+
+	return Interpolate(GopherpanicSourceURL, Args{
+		"repository": majorVersionSuffix.ReplaceAllString(mainModulePath, ""),
+		"module":     mainModulePath,
+		"revision":   GopherpanicRevision,
+		"file":       file,
+		"line":       position.Line,
+	})
+}
+
+// Path of a file relative to its module root
+func moduleFile(file string) (string, bool) {
+	if !filepath.IsAbs(file) {
+		return strings.CutPrefix(file, mainModulePath+"/")
+	}
+
+	root := moduleRoot(filepath.Dir(file))
+	if root == "" {
+		return "", false
+	}
+
+	relative, err := filepath.Rel(root, file)
+	if err != nil {
+		return "", false
+	}
+
+	return filepath.ToSlash(relative), true
+}
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+	ansiFaint  = "\x1b[2m"
+)
+
+// Convert into colored GNU format for terminals.
+//
+// The positions are OSC 8 hyperlinks to their SourceURL when the source links are enabled.
+// The Violations are listed after the message like Format.
+func (err Error) FormatColor(withTraces bool) string {
+	message := textMessage(err.Message)
+	result := fmt.Sprintf(
+		"%s %s%s%s%s %s%d:%s%s:%s",
+		formatColorPosition(err.Position),
+		ansiBold,
+		err.Severity.color(),
+		err.Severity.label(),
+		ansiReset,
+		ansiYellow,
+		err.Code.ID,
+		escapeText(err.Code.Description),
+		ansiReset,
+		message,
+	) + err.Violations.format(false)
+
+	if !withTraces {
+		return result
+	}
+
+	return iterago.Fold(err.Traces, result, func(acc string, trace Trace) string {
+		return acc + fmt.Sprintf("\n%s %sError:%s %s", formatColorPosition(trace.Position), ansiFaint, ansiReset, textMessage(trace.Message))
+	})
+}
+
+func formatColorPosition(position Position) string {
+	text := fmt.Sprintf("%s%s:%d:%s", ansiBold, escapeText(position.TrimmedFile()), position.Line, ansiReset)
+	if function := position.formatFunction(false); function != "" {
+		text += " " + strings.TrimSuffix(function, ": ") + ":"
+	}
+
+	url := position.SourceURL()
+	if url == "" {
+		return text
+	}
+
+	return hyperlink(url, text)
+}
+
+// OSC 8 terminal hyperlink
+func hyperlink(url string, text string) string {
+	return "\x1b]8;;" + escapeText(url) + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
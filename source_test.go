@@ -0,0 +1,150 @@
+package gopherpanic
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setSourceLinks(t *testing.T, url string, revision string) {
+	sourceURL, sourceRevision := GopherpanicSourceURL, GopherpanicRevision
+	GopherpanicSourceURL, GopherpanicRevision = url, revision
+	t.Cleanup(func() { GopherpanicSourceURL, GopherpanicRevision = sourceURL, sourceRevision })
+}
+
+func TestPositionSourceURL(t *testing.T) {
+	spawned := Position{}.Spawn()
+
+	tests := []struct {
+		name     string
+		fields   Position
+		url      string
+		revision string
+		want     string
+	}{
+		{
+			name:     "OK - GitHub",
+			fields:   spawned,
+			url:      GitHubSourceURL,
+			revision: "8f2c1e7",
+			want:     "https://github.com/ulphidius/gopherpanic/blob/8f2c1e7/source_test.go#L17",
+		},
+		{
+			name:     "OK - GitLab",
+			fields:   spawned,
+			url:      GitLabSourceURL,
+			revision: "8f2c1e7",
+			want:     "https://github.com/ulphidius/gopherpanic/-/blob/8f2c1e7/source_test.go#L17",
+		},
+		{
+			name:     "OK - custom template",
+			fields:   spawned,
+			url:      "https://git.example.com/{module}/tree/{revision}/{file}?line={line}",
+			revision: "8f2c1e7",
+			want:     "https://git.example.com/github.com/ulphidius/gopherpanic/tree/8f2c1e7/source_test.go?line=17",
+		},
+		{
+			name:     "OK - trimpath file",
+			fields:   Position{File: "github.com/ulphidius/gopherpanic/cli/docs.go", Line: 12, Package: "github.com/ulphidius/gopherpanic/cli"},
+			url:      GiteaSourceURL,
+			revision: "8f2c1e7",
+			want:     "https://github.com/ulphidius/gopherpanic/src/commit/8f2c1e7/cli/docs.go#L12",
+		},
+		{
+			name:     "OK - dependency",
+			fields:   Position{File: "github.com/stretchr/testify@v1.8.2/assert/assertions.go", Line: 12, Package: "github.com/stretchr/testify/assert"},
+			url:      GitHubSourceURL,
+			revision: "8f2c1e7",
+			want:     "",
+		},
+		{
+			name:     "OK - disabled",
+			fields:   spawned,
+			url:      "",
+			revision: "8f2c1e7",
+			want:     "",
+		},
+		{
+			name:     "OK - unknown revision",
+			fields:   spawned,
+			url:      GitHubSourceURL,
+			revision: "",
+			want:     "",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			setSourceLinks(t, testCase.url, testCase.revision)
+			assert.Equal(t, testCase.want, testCase.fields.SourceURL())
+		})
+	}
+}
+
+func TestPositionMarshalJSONSourceURL(t *testing.T) {
+	setSourceLinks(t, GitHubSourceURL, "8f2c1e7")
+
+	position := Position{File: "github.com/ulphidius/gopherpanic/error.go", Line: 12, Function: "github.com/ulphidius/gopherpanic.New", Package: "github.com/ulphidius/gopherpanic"}
+	data, err := position.MarshalJSON()
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		`{"file":"github.com/ulphidius/gopherpanic/error.go","line":12,"function":"github.com/ulphidius/gopherpanic.New","package":"github.com/ulphidius/gopherpanic","url":"https://github.com/ulphidius/gopherpanic/blob/8f2c1e7/error.go#L12"}`,
+		string(data),
+	)
+}
+
+func TestErrorFormatColor(t *testing.T) {
+	position := Position{File: "github.com/ulphidius/gopherpanic/error.go", Line: 12, Package: "github.com/ulphidius/gopherpanic"}
+	err := Error{Code: IOError, Message: "disk full", Position: position, Traces: []Trace{{Message: "write failed", Position: Position{File: "main.go", Line: 3}}}}
+
+	tests := []struct {
+		name string
+		url  string
+		args bool
+		want string
+	}{
+		{
+			name: "OK - without source links",
+			url:  "",
+			args: false,
+			want: "\x1b[1mgithub.com/ulphidius/gopherpanic/error.go:12:\x1b[0m \x1b[1m\x1b[31mError:\x1b[0m \x1b[33m1:failed to perform IO task\x1b[0m:disk full",
+		},
+		{
+			name: "OK - hyperlinks and traces",
+			url:  GitHubSourceURL,
+			args: true,
+			want: strings.Join([]string{
+				"\x1b]8;;https://github.com/ulphidius/gopherpanic/blob/8f2c1e7/error.go#L12\x1b\\\x1b[1mgithub.com/ulphidius/gopherpanic/error.go:12:\x1b[0m\x1b]8;;\x1b\\ \x1b[1m\x1b[31mError:\x1b[0m \x1b[33m1:failed to perform IO task\x1b[0m:disk full",
+				"\x1b[1mmain.go:3:\x1b[0m \x1b[2mError:\x1b[0m write failed",
+			}, "\n"),
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			setSourceLinks(t, testCase.url, "8f2c1e7")
+			assert.Equal(t, testCase.want, err.FormatColor(testCase.args))
+		})
+	}
+}
+
+func TestErrorFormatColorViolations(t *testing.T) {
+	setSourceLinks(t, "", "")
+	err := NewValidation(
+		Violation{Field: "name", Constraint: "required", Message: "name is required"},
+		Violation{Field: "age", Constraint: "min", Value: -1, Message: "age must be positive"},
+	)
+	err.Position = Position{File: "user.go", Line: 12}
+
+	want := strings.Join([]string{
+		"\x1b[1muser.go:12:\x1b[0m \x1b[1m\x1b[31mError:\x1b[0m \x1b[33m4:failed to perform client api task\x1b[0m:2 invalid fields",
+		"\tname: name is required [required]",
+		"\tage: age must be positive [min, rejected: -1]",
+	}, "\n")
+
+	assert.Equal(t, want, err.FormatColor(false))
+	assert.True(t, strings.HasSuffix(err.Format(false, true), strings.Join(strings.Split(want, "\n")[1:], "\n")))
+}
@@ -0,0 +1,107 @@
+package gopherpanic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Structured arguments of a message template
+type Args map[string]any
+
+// Create a new error whose message is the interpolation of a template.
+//
+// The placeholders are written {name} and are replaced by the argument with the same name,
+// {{ and }} are rendered as literal braces. The template and the arguments are kept in the error
+// to group the errors by template.
+func NewTemplate(code Code, template string, args Args) *Error {
+	err := ErrorBuilder{}.New().
+		WithCode(code).
+		WithTemplate(template, args).
+		WithPosition(Position{}.spawn(2)).
+		Build()
+	return &err
+}
+
+// Create a new error with the message template registered for the code in the DefaultCatalog.
+//
+// The code description is used as template if the code has no registered template.
+func NewFromCatalog(code Code, args Args) *Error {
+	template := code.Description
+	if entry, exists := DefaultCatalog.LookupCode(code); exists && entry.Template != "" {
+		template = entry.Template
+	}
+
+	err := ErrorBuilder{}.New().
+		WithCode(code).
+		WithTemplate(template, args).
+		WithPosition(Position{}.spawn(2)).
+		Build()
+	return &err
+}
+
+// Replace the placeholders of a template by the arguments.
+//
+// Placeholders without argument are kept as is.
+func Interpolate(template string, args Args) string {
+	var builder strings.Builder
+	walkTemplate(template, func(text string, placeholder bool) {
+		if !placeholder {
+			builder.WriteString(text)
+			return
+		}
+
+		value, exists := args[text]
+		if !exists {
+			builder.WriteString("{" + text + "}")
+			return
+		}
+
+		builder.WriteString(fmt.Sprint(value))
+	})
+
+	return builder.String()
+}
+
+// List the placeholder names of a template in order of first appearance
+func TemplatePlaceholders(template string) []string {
+	names := []string{}
+	seen := map[string]bool{}
+	walkTemplate(template, func(text string, placeholder bool) {
+		if placeholder && !seen[text] {
+			seen[text] = true
+			names = append(names, text)
+		}
+	})
+
+	return names
+}
+
+func walkTemplate(template string, visit func(text string, placeholder bool)) {
+	for len(template) > 0 {
+		switch {
+		case strings.HasPrefix(template, "{{"):
+			visit("{", false)
+			template = template[2:]
+		case strings.HasPrefix(template, "}}"):
+			visit("}", false)
+			template = template[2:]
+		case template[0] == '{':
+			end := strings.IndexAny(template[1:], "{}")
+			if end < 0 || template[end+1] != '}' || end == 0 {
+				visit("{", false)
+				template = template[1:]
+				continue
+			}
+			visit(template[1:end+1], true)
+			template = template[end+2:]
+		default:
+			end := strings.IndexAny(template[1:], "{}")
+			if end < 0 {
+				visit(template, false)
+				return
+			}
+			visit(template[:end+1], false)
+			template = template[end+1:]
+		}
+	}
+}
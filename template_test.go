@@ -0,0 +1,152 @@
+package gopherpanic
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var templateTestCode = Code{ID: 1000, Description: "template test"}
+
+func init() {
+	MustRegister(CatalogEntry{Code: templateTestCode, Name: "TemplateTest", Template: "user {user_id} not found in {table}"})
+}
+
+func ExampleNewTemplate() {
+	err := NewTemplate(ClientError, "user {user_id} not found in {table}", Args{"user_id": 42, "table": "users"})
+	err.Position = Position{File: "main.go", Line: 30}
+
+	fmt.Println(err.Error())
+	fmt.Println(err.FormatJSON(false))
+	// Output:
+	// main.go:30: Error: 4:failed to perform client api task:user 42 not found in users
+	// {"code":{"id":4,"description":"failed to perform client api task"},"message":"user 42 not found in users","position":{"file":"main.go","line":30},"template":"user {user_id} not found in {table}","args":{"table":"users","user_id":42}}
+}
+
+func TestInterpolate(t *testing.T) {
+	type args struct {
+		template string
+		args     Args
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "OK",
+			args: args{template: "user {user_id} not found in {table}", args: Args{"user_id": 42, "table": "users"}},
+			want: "user 42 not found in users",
+		},
+		{
+			name: "OK - missing argument",
+			args: args{template: "user {user_id} not found in {table}", args: Args{"user_id": 42}},
+			want: "user 42 not found in {table}",
+		},
+		{
+			name: "OK - escaped braces",
+			args: args{template: "{{user_id}} is {user_id}", args: Args{"user_id": 42}},
+			want: "{user_id} is 42",
+		},
+		{
+			name: "OK - unbalanced braces",
+			args: args{template: "{} { {a{b} c}", args: Args{"b": 1}},
+			want: "{} { {a1 c}",
+		},
+		{
+			name: "OK - without template",
+			args: args{template: "", args: nil},
+			want: "",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := Interpolate(testCase.args.template, testCase.args.args)
+			assert.Equal(t, testCase.want, result)
+		})
+	}
+}
+
+func TestTemplatePlaceholders(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want []string
+	}{
+		{
+			name: "OK",
+			args: "user {user_id} not found in {table}, {user_id}",
+			want: []string{"user_id", "table"},
+		},
+		{
+			name: "OK - escaped braces",
+			args: "{{user_id}}",
+			want: []string{},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := TemplatePlaceholders(testCase.args)
+			assert.Equal(t, testCase.want, result)
+		})
+	}
+}
+
+func TestNewTemplate(t *testing.T) {
+	result := NewTemplate(ClientError, "user {user_id} not found", Args{"user_id": 42}) // Error check based on the current line
+	files := strings.Split(result.Position.File, "/")
+	result.Position.File = files[len(files)-1]
+
+	assert.Equal(t, &Error{
+		Code:     ClientError,
+		Message:  "user 42 not found",
+		Position: Position{File: "template_test.go", Line: 101, Function: "github.com/ulphidius/gopherpanic.TestNewTemplate", Package: "github.com/ulphidius/gopherpanic"},
+		Template: "user {user_id} not found",
+		Args:     Args{"user_id": 42},
+	}, result)
+}
+
+func TestNewFromCatalog(t *testing.T) {
+	tests := []struct {
+		name string
+		args Code
+		want *Error
+	}{
+		{
+			name: "OK - registered template",
+			args: templateTestCode,
+			want: &Error{
+				Code:     templateTestCode,
+				Message:  "user 42 not found in users",
+				Position: Position{File: "template_test.go", Line: 146, Function: "github.com/ulphidius/gopherpanic.TestNewFromCatalog.func1", Package: "github.com/ulphidius/gopherpanic"},
+				Template: "user {user_id} not found in {table}",
+				Args:     Args{"user_id": 42, "table": "users"},
+			},
+		},
+		{
+			name: "OK - description as template",
+			args: ClientError,
+			want: &Error{
+				Code:     ClientError,
+				Message:  "failed to perform client api task",
+				Position: Position{File: "template_test.go", Line: 146, Function: "github.com/ulphidius/gopherpanic.TestNewFromCatalog.func1", Package: "github.com/ulphidius/gopherpanic"},
+				Template: "failed to perform client api task",
+				Args:     Args{"user_id": 42, "table": "users"},
+			},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := NewFromCatalog(testCase.args, Args{"user_id": 42, "table": "users"}) // Error check based on the current line
+			files := strings.Split(result.Position.File, "/")
+			result.Position.File = files[len(files)-1]
+			assert.Equal(t, testCase.want, result)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package gopherpanic
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+type TimeRendering uint
+
+const (
+	TimeHidden   TimeRendering = iota // The timestamps are not rendered
+	TimeAbsolute                      // RFC 3339 timestamp: (at 2024-01-24T10:00:00.000Z)
+	TimeRelative                      // Elapsed time since the oldest error of the chain: (+1.2s)
+)
+
+// Record the creation time of every Error.
+//
+// Initialized from GOPHERPANIC_TIMESTAMPS (true or false). The time is kept by the traces on wrap
+// and serialized in RFC 3339 by FormatJSON.
+var GopherpanicTimestamps bool = false
+
+// Rendering of the timestamps by FormatWithTraces.
+//
+// Initialized from GOPHERPANIC_TIME_RENDERING (0: hidden, 1: absolute, 2: relative).
+var GopherpanicTimeRendering TimeRendering = TimeHidden
+
+// Clock used to timestamp the errors, replaceable for deterministic tests
+var Clock func() time.Time = time.Now
+
+const absoluteTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+func init() {
+	if enabled, err := strconv.ParseBool(os.Getenv("GOPHERPANIC_TIMESTAMPS")); err == nil {
+		GopherpanicTimestamps = enabled
+	}
+
+	rendering, err := strconv.Atoi(os.Getenv("GOPHERPANIC_TIME_RENDERING"))
+	if err == nil && rendering >= 0 && rendering <= 2 {
+		GopherpanicTimeRendering = TimeRendering(rendering)
+	}
+}
+
+// Creation time of a new Error, nil if GopherpanicTimestamps is disabled
+func newTimestamp() *time.Time {
+	if !GopherpanicTimestamps || Clock == nil {
+		return nil
+	}
+
+	now := Clock()
+	return &now
+}
+
+// Oldest timestamp of the error and its traces
+func (err Error) oldestTime() time.Time {
+	oldest := time.Time{}
+	times := []*time.Time{err.Time}
+	for _, trace := range err.Traces {
+		times = append(times, trace.Time)
+	}
+
+	for _, timestamp := range times {
+		if timestamp != nil && (oldest.IsZero() || timestamp.Before(oldest)) {
+			oldest = *timestamp
+		}
+	}
+
+	return oldest
+}
+
+// Timestamp suffix of a line rendered by FormatWithTraces
+func formatTime(timestamp *time.Time, reference time.Time, custom bool) string {
+	if timestamp == nil || GopherpanicTimeRendering == TimeHidden {
+		return ""
+	}
+
+	if GopherpanicTimeRendering == TimeRelative {
+		elapsed := "+" + timestamp.Sub(reference).Round(time.Millisecond).String()
+		if custom {
+			return "; elapsed: " + elapsed
+		}
+		return fmt.Sprintf(" (%s)", elapsed)
+	}
+
+	if custom {
+		return "; at time: " + timestamp.Format(absoluteTimeLayout)
+	}
+	return fmt.Sprintf(" (at %s)", timestamp.Format(absoluteTimeLayout))
+}
@@ -0,0 +1,101 @@
+package gopherpanic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func enableTimestamps(t *testing.T, rendering TimeRendering, times ...time.Time) {
+	enabled, timeRendering, clock := GopherpanicTimestamps, GopherpanicTimeRendering, Clock
+	t.Cleanup(func() { GopherpanicTimestamps, GopherpanicTimeRendering, Clock = enabled, timeRendering, clock })
+
+	GopherpanicTimestamps = true
+	GopherpanicTimeRendering = rendering
+	Clock = func() time.Time {
+		now := times[0]
+		times = times[1:]
+		return now
+	}
+}
+
+func TestTimestamps(t *testing.T) {
+	start := time.Date(2024, 1, 24, 10, 0, 0, 0, time.UTC)
+	enableTimestamps(t, TimeHidden, start, start.Add(time.Second), start.Add(2*time.Second))
+
+	root := New(IOError, "disk full")
+	wrapped := Wrap(InternalError, "cannot save", root)
+	built := ErrorBuilder{}.New().Build()
+
+	assert.Equal(t, start, *root.Time)
+	assert.Equal(t, start.Add(time.Second), *wrapped.Time)
+	assert.Equal(t, start, *wrapped.Traces[0].Time)
+	assert.Equal(t, start.Add(2*time.Second), *built.Time)
+	assert.Equal(t, start, *wrapped.Traces[0].IntoError().Time)
+	assert.Contains(t, wrapped.FormatJSON(false), `"time":"2024-01-24T10:00:01Z"`)
+}
+
+func TestTimestampsDisabled(t *testing.T) {
+	assert.Nil(t, New(IOError, "disk full").Time)
+	assert.Nil(t, ErrorBuilder{}.New().Build().Time)
+}
+
+func TestErrorBuilderWithTime(t *testing.T) {
+	timestamp := time.Date(2024, 1, 24, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, &timestamp, ErrorBuilder{}.New().WithTime(timestamp).Build().Time)
+}
+
+func TestFormatWithTracesTime(t *testing.T) {
+	start := time.Date(2024, 1, 24, 10, 0, 0, 0, time.UTC)
+	root, reported := start, start.Add(1200*time.Millisecond)
+	err := Error{
+		Code:     InternalError,
+		Message:  "cannot save",
+		Position: Position{File: "sample.go", Line: 50},
+		Time:     &reported,
+		Traces: []Trace{
+			{Message: "disk full", Position: Position{File: "sample.go", Line: 40}, Time: &root},
+			{Message: "untimed", Position: Position{File: "sample.go", Line: 30}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		rendering TimeRendering
+		custom    bool
+		want      string
+	}{
+		{
+			name:      "OK - hidden",
+			rendering: TimeHidden,
+			want:      "sample.go:50: Error: 3:failed to perform application task:cannot save\nsample.go:40: Error: disk full\nsample.go:30: Error: untimed",
+		},
+		{
+			name:      "OK - absolute",
+			rendering: TimeAbsolute,
+			want:      "sample.go:50: Error: 3:failed to perform application task:cannot save (at 2024-01-24T10:00:01.200Z)\nsample.go:40: Error: disk full (at 2024-01-24T10:00:00.000Z)\nsample.go:30: Error: untimed",
+		},
+		{
+			name:      "OK - relative",
+			rendering: TimeRelative,
+			want:      "sample.go:50: Error: 3:failed to perform application task:cannot save (+1.2s)\nsample.go:40: Error: disk full (+0s)\nsample.go:30: Error: untimed",
+		},
+		{
+			name:      "OK - relative custom",
+			rendering: TimeRelative,
+			custom:    true,
+			want:      "code id: 3; description: failed to perform application task\n\terror message: cannot save; in file: sample.go; at line: 50; elapsed: +1.2s\n\t\ttrace message: disk full; in file: sample.go; at line: 40; elapsed: +0s\n\t\ttrace message: untimed; in file: sample.go; at line: 30",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			rendering := GopherpanicTimeRendering
+			GopherpanicTimeRendering = testCase.rendering
+			defer func() { GopherpanicTimeRendering = rendering }()
+
+			assert.Equal(t, testCase.want, err.FormatWithTraces(testCase.custom))
+		})
+	}
+}
@@ -0,0 +1,639 @@
+// Package analyzer reports the misuses of gopherpanic errors.
+//
+// The Analyzer can be run with go vet:
+//
+//	go install github.com/ulphidius/gopherpanic/tools/cmd/gopherpanic-vet
+//	go vet -vettool=$(which gopherpanic-vet) ./...
+package analyzer
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const gopherpanicPath = "github.com/ulphidius/gopherpanic"
+
+var errorType = types.Universe.Lookup("error").Type()
+
+var Analyzer = &analysis.Analyzer{
+	Name: "gopherpanic",
+	Doc: `report misuses of gopherpanic errors
+
+- typednil: a *gopherpanic.Error which can be nil is converted into error, the resulting interface is never nil
+- discarded: the *gopherpanic.Error returned by a call is ignored
+- wrapmessage: the message given to Wrap duplicates the message of the wrapped error`,
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	Run:       run,
+	FactTypes: []analysis.Fact{new(neverNilFact)},
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	messages := map[types.Object]string{}
+	nils := newNilness(pass)
+
+	filter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+		(*ast.FuncLit)(nil),
+		(*ast.ReturnStmt)(nil),
+		(*ast.AssignStmt)(nil),
+		(*ast.ValueSpec)(nil),
+		(*ast.ExprStmt)(nil),
+		(*ast.CallExpr)(nil),
+	}
+
+	var signatures []*types.Signature
+	inspect.WithStack(filter, func(node ast.Node, push bool, stack []ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncDecl:
+			signatures = pushSignature(signatures, pass.TypesInfo.TypeOf(node.Name), push)
+		case *ast.FuncLit:
+			signatures = pushSignature(signatures, pass.TypesInfo.TypeOf(node), push)
+		}
+
+		// The messages are recorded once the assigned value is checked: err = Wrap(code, "message", err)
+		if !push {
+			recordMessages(pass, node, messages)
+			return true
+		}
+
+		switch node := node.(type) {
+		case *ast.ReturnStmt:
+			checkReturn(pass, node, signatures, stack, nils)
+		case *ast.AssignStmt:
+			checkAssign(pass, node, stack, nils)
+		case *ast.ValueSpec:
+			checkValueSpec(pass, node, stack, nils)
+		case *ast.ExprStmt:
+			checkDiscardedCall(pass, node.X)
+		case *ast.CallExpr:
+			checkConversion(pass, node, stack, nils)
+			checkWrapMessage(pass, node, messages)
+		}
+
+		return true
+	})
+
+	return nil, nil
+}
+
+func pushSignature(signatures []*types.Signature, typ types.Type, push bool) []*types.Signature {
+	if !push {
+		return signatures[:len(signatures)-1]
+	}
+
+	signature, _ := typ.(*types.Signature)
+	return append(signatures, signature)
+}
+
+// Report the *Error values returned through an error result
+func checkReturn(pass *analysis.Pass, node *ast.ReturnStmt, signatures []*types.Signature, stack []ast.Node, nils *nilness) {
+	if len(signatures) == 0 || signatures[len(signatures)-1] == nil {
+		return
+	}
+
+	results := signatures[len(signatures)-1].Results()
+	if results.Len() != len(node.Results) {
+		return
+	}
+
+	for index, result := range node.Results {
+		reportTypedNil(pass, result, results.At(index).Type(), stack, nils)
+	}
+}
+
+// Report the *Error values assigned to error variables and the ignored *Error results
+func checkAssign(pass *analysis.Pass, node *ast.AssignStmt, stack []ast.Node, nils *nilness) {
+	if len(node.Lhs) == len(node.Rhs) && node.Tok == token.ASSIGN {
+		for index, value := range node.Rhs {
+			reportTypedNil(pass, value, pass.TypesInfo.TypeOf(node.Lhs[index]), stack, nils)
+		}
+	}
+
+	if len(node.Rhs) != 1 {
+		return
+	}
+
+	call, isCall := node.Rhs[0].(*ast.CallExpr)
+	if !isCall {
+		return
+	}
+
+	tuple, isTuple := pass.TypesInfo.TypeOf(call).(*types.Tuple)
+	for index, target := range node.Lhs {
+		ident, isIdent := target.(*ast.Ident)
+		if !isIdent || ident.Name != "_" {
+			continue
+		}
+
+		if isTuple && index < tuple.Len() && isErrorPointer(tuple.At(index).Type()) {
+			pass.Reportf(target.Pos(), "discarded: the *gopherpanic.Error returned by %s is ignored", callName(call))
+		}
+
+		if !isTuple && isErrorPointer(pass.TypesInfo.TypeOf(call)) {
+			pass.Reportf(target.Pos(), "discarded: the *gopherpanic.Error returned by %s is ignored", callName(call))
+		}
+	}
+}
+
+// Report the *Error values used to initialize error variables
+func checkValueSpec(pass *analysis.Pass, node *ast.ValueSpec, stack []ast.Node, nils *nilness) {
+	if len(node.Names) != len(node.Values) || node.Type == nil {
+		return
+	}
+
+	for _, value := range node.Values {
+		reportTypedNil(pass, value, pass.TypesInfo.TypeOf(node.Type), stack, nils)
+	}
+}
+
+// Report the calls used as statement which return a *Error
+func checkDiscardedCall(pass *analysis.Pass, expression ast.Expr) {
+	call, isCall := astutil.Unparen(expression).(*ast.CallExpr)
+	if !isCall {
+		return
+	}
+
+	typ := pass.TypesInfo.TypeOf(call)
+	if tuple, isTuple := typ.(*types.Tuple); isTuple {
+		for index := 0; index < tuple.Len(); index++ {
+			if isErrorPointer(tuple.At(index).Type()) {
+				pass.Reportf(call.Pos(), "discarded: the *gopherpanic.Error returned by %s is ignored", callName(call))
+				return
+			}
+		}
+		return
+	}
+
+	if isErrorPointer(typ) {
+		pass.Reportf(call.Pos(), "discarded: the *gopherpanic.Error returned by %s is ignored", callName(call))
+	}
+}
+
+// Report the explicit conversions error(x) and the *Error arguments given to error parameters
+func checkConversion(pass *analysis.Pass, call *ast.CallExpr, stack []ast.Node, nils *nilness) {
+	if typeAndValue, exists := pass.TypesInfo.Types[call.Fun]; exists && typeAndValue.IsType() {
+		if len(call.Args) == 1 {
+			reportTypedNil(pass, call.Args[0], typeAndValue.Type, stack, nils)
+		}
+		return
+	}
+
+	signature, isSignature := pass.TypesInfo.TypeOf(call.Fun).(*types.Signature)
+	if !isSignature {
+		return
+	}
+
+	params := signature.Params()
+	for index, argument := range call.Args {
+		switch {
+		case signature.Variadic() && index >= params.Len()-1:
+			if call.Ellipsis.IsValid() {
+				continue
+			}
+			slice, _ := params.At(params.Len() - 1).Type().(*types.Slice)
+			if slice != nil {
+				reportTypedNil(pass, argument, slice.Elem(), stack, nils)
+			}
+		case index < params.Len():
+			reportTypedNil(pass, argument, params.At(index).Type(), stack, nils)
+		}
+	}
+}
+
+// Report the Wrap calls whose message is the message of the wrapped error
+func checkWrapMessage(pass *analysis.Pass, call *ast.CallExpr, messages map[types.Object]string) {
+	if !isGopherpanicFunc(pass, call, "Wrap") || len(call.Args) != 3 {
+		return
+	}
+
+	message, wrapped := call.Args[1], astutil.Unparen(call.Args[2])
+
+	// Wrap(code, err.Message, err)
+	if selector, isSelector := astutil.Unparen(message).(*ast.SelectorExpr); isSelector && selector.Sel.Name == "Message" {
+		if sameObject(pass, selector.X, wrapped) {
+			pass.Reportf(message.Pos(), "wrapmessage: the Wrap message duplicates the message of the wrapped error")
+			return
+		}
+	}
+
+	value := constantString(pass, message)
+	if value == nil {
+		return
+	}
+
+	wrappedMessage := ""
+	switch wrapped := wrapped.(type) {
+	case *ast.CallExpr:
+		wrappedMessage = constructorMessage(pass, wrapped)
+	case *ast.Ident:
+		wrappedMessage = messages[pass.TypesInfo.ObjectOf(wrapped)]
+	}
+
+	if wrappedMessage != "" && wrappedMessage == *value {
+		pass.Reportf(message.Pos(), "wrapmessage: the Wrap message duplicates the message of the wrapped error")
+	}
+}
+
+// Remember the constant messages of the errors assigned by the statement
+func recordMessages(pass *analysis.Pass, node ast.Node, messages map[types.Object]string) {
+	switch node := node.(type) {
+	case *ast.AssignStmt:
+		if len(node.Lhs) == len(node.Rhs) {
+			for index, value := range node.Rhs {
+				recordMessage(pass, node.Lhs[index], value, messages)
+			}
+		}
+	case *ast.ValueSpec:
+		if len(node.Names) == len(node.Values) {
+			for index, value := range node.Values {
+				recordMessage(pass, node.Names[index], value, messages)
+			}
+		}
+	}
+}
+
+// Remember the constant message of the errors created by New or Wrap
+func recordMessage(pass *analysis.Pass, target ast.Expr, value ast.Expr, messages map[types.Object]string) {
+	ident, isIdent := target.(*ast.Ident)
+	call, isCall := astutil.Unparen(value).(*ast.CallExpr)
+	if !isIdent || !isCall {
+		return
+	}
+
+	object := pass.TypesInfo.ObjectOf(ident)
+	if object == nil {
+		return
+	}
+
+	if message := constructorMessage(pass, call); message != "" {
+		messages[object] = message
+		return
+	}
+
+	delete(messages, object)
+}
+
+func constructorMessage(pass *analysis.Pass, call *ast.CallExpr) string {
+	if !isGopherpanicFunc(pass, call, "New") && !isGopherpanicFunc(pass, call, "Wrap") {
+		return ""
+	}
+
+	if len(call.Args) < 2 {
+		return ""
+	}
+
+	if value := constantString(pass, call.Args[1]); value != nil {
+		return *value
+	}
+
+	return ""
+}
+
+func reportTypedNil(pass *analysis.Pass, value ast.Expr, target types.Type, stack []ast.Node, nils *nilness) {
+	if target == nil || !types.Identical(target, errorType) || !isErrorPointer(pass.TypesInfo.TypeOf(value)) || nils.neverNil(value) {
+		return
+	}
+
+	if ident, isIdent := astutil.Unparen(value).(*ast.Ident); isIdent && checkedNotNil(pass, pass.TypesInfo.ObjectOf(ident), stack) {
+		return
+	}
+
+	pass.Reportf(value.Pos(), "typednil: a nil *gopherpanic.Error converted into error is not a nil interface")
+}
+
+// Fact of the functions whose *Error results are never nil
+type neverNilFact struct{}
+
+func (*neverNilFact) AFact() {}
+
+func (*neverNilFact) String() string { return "neverNil" }
+
+// Functions and local variables of the package which cannot produce a nil *Error
+type nilness struct {
+	pass        *analysis.Pass
+	functions   map[*types.Func]bool
+	constructed map[types.Object]bool
+}
+
+// Find the never nil functions and variables of the package and export the function facts.
+//
+// The functions calling each other are resolved by repeating the search until nothing changes.
+func newNilness(pass *analysis.Pass) *nilness {
+	nils := &nilness{pass: pass, functions: map[*types.Func]bool{}, constructed: map[types.Object]bool{}}
+	for {
+		nils.constructed = nils.constructedVariables()
+		if !nils.findFunctions() {
+			break
+		}
+	}
+
+	for function := range nils.functions {
+		pass.ExportObjectFact(function, new(neverNilFact))
+	}
+
+	return nils
+}
+
+// Expressions which cannot produce a nil *Error
+func (nils *nilness) neverNil(value ast.Expr) bool {
+	switch value := astutil.Unparen(value).(type) {
+	case *ast.UnaryExpr:
+		return value.Op == token.AND
+	case *ast.Ident:
+		return nils.constructed[nils.pass.TypesInfo.ObjectOf(value)]
+	case *ast.CallExpr:
+		function, isFunc := typeutil.Callee(nils.pass.TypesInfo, value).(*types.Func)
+		if !isFunc {
+			return false
+		}
+		if function.Pkg() == nils.pass.Pkg {
+			return nils.functions[function]
+		}
+		return nils.pass.ImportObjectFact(function, new(neverNilFact))
+	}
+
+	return false
+}
+
+// Add the functions whose *Error results are never nil, reports if a function is added
+func (nils *nilness) findFunctions() bool {
+	added := false
+	for _, file := range nils.pass.Files {
+		for _, declaration := range file.Decls {
+			function, isFunc := declaration.(*ast.FuncDecl)
+			if !isFunc || function.Body == nil {
+				continue
+			}
+
+			object, isObject := nils.pass.TypesInfo.Defs[function.Name].(*types.Func)
+			if !isObject || nils.functions[object] || !nils.returnsNeverNil(object.Type().(*types.Signature), function.Body) {
+				continue
+			}
+
+			nils.functions[object] = true
+			added = true
+		}
+	}
+
+	return added
+}
+
+// Every return statement of the body gives never nil values to the *Error results
+func (nils *nilness) returnsNeverNil(signature *types.Signature, body *ast.BlockStmt) bool {
+	var indexes []int
+	for index := 0; index < signature.Results().Len(); index++ {
+		if isErrorPointer(signature.Results().At(index).Type()) {
+			indexes = append(indexes, index)
+		}
+	}
+	if len(indexes) == 0 {
+		return false
+	}
+
+	safe := true
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(node.Results) != signature.Results().Len() {
+				safe = false
+				return false
+			}
+			for _, index := range indexes {
+				safe = safe && nils.neverNil(node.Results[index])
+			}
+		}
+		return safe
+	})
+
+	return safe
+}
+
+// Local *Error variables whose every assignment is a never nil expression
+func (nils *nilness) constructedVariables() map[types.Object]bool {
+	pass := nils.pass
+	constructed := map[types.Object]bool{}
+	assigned := func(ident *ast.Ident, value ast.Expr) {
+		object := pass.TypesInfo.ObjectOf(ident)
+		if object == nil || !isErrorPointer(object.Type()) {
+			return
+		}
+
+		safe, exists := constructed[object]
+		constructed[object] = (safe || !exists) && value != nil && nils.neverNil(value) && object.Parent() != object.Pkg().Scope()
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.AssignStmt:
+				for index, target := range node.Lhs {
+					if ident, isIdent := astutil.Unparen(target).(*ast.Ident); isIdent {
+						var value ast.Expr
+						if len(node.Lhs) == len(node.Rhs) {
+							value = node.Rhs[index]
+						}
+						assigned(ident, value)
+					}
+				}
+			case *ast.ValueSpec:
+				for index, name := range node.Names {
+					var value ast.Expr
+					if len(node.Names) == len(node.Values) {
+						value = node.Values[index]
+					}
+					assigned(name, value)
+				}
+			case *ast.UnaryExpr:
+				// The variable can be assigned through its address
+				if ident, isIdent := astutil.Unparen(node.X).(*ast.Ident); isIdent && node.Op == token.AND {
+					assigned(ident, nil)
+				}
+			case *ast.FuncType:
+				// The parameters and results start with the value given by the caller or nil
+				for _, fields := range []*ast.FieldList{node.Params, node.Results} {
+					if fields == nil {
+						continue
+					}
+					for _, field := range fields.List {
+						for _, name := range field.Names {
+							assigned(name, nil)
+						}
+					}
+				}
+			case *ast.RangeStmt:
+				for _, target := range []ast.Expr{node.Key, node.Value} {
+					if ident, isIdent := target.(*ast.Ident); isIdent {
+						assigned(ident, nil)
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	return constructed
+}
+
+// The current node is guarded by an enclosing if statement which proves that the variable is not nil:
+//
+//	if err != nil { <node> }
+//	if err == nil { ... } else { <node> }
+//	if err == nil { return } <node>
+func checkedNotNil(pass *analysis.Pass, object types.Object, stack []ast.Node) bool {
+	if object == nil {
+		return false
+	}
+
+	for index := len(stack) - 2; index >= 0; index-- {
+		child := stack[index+1]
+		switch parent := stack[index].(type) {
+		case *ast.IfStmt:
+			if child == parent.Body && impliesNotNil(pass, parent.Cond, object, false) {
+				return true
+			}
+			if child == parent.Else && impliesNotNil(pass, parent.Cond, object, true) {
+				return true
+			}
+		case *ast.BlockStmt:
+			for _, statement := range parent.List {
+				if statement == child {
+					break
+				}
+
+				guard, isIf := statement.(*ast.IfStmt)
+				if isIf && guard.Else == nil && terminates(guard.Body) && impliesNotNil(pass, guard.Cond, object, true) {
+					return true
+				}
+			}
+		case *ast.FuncDecl, *ast.FuncLit:
+			return false
+		}
+	}
+
+	return false
+}
+
+// The condition (or its negation) proves that the variable is not nil
+func impliesNotNil(pass *analysis.Pass, condition ast.Expr, object types.Object, negated bool) bool {
+	switch condition := astutil.Unparen(condition).(type) {
+	case *ast.UnaryExpr:
+		return condition.Op == token.NOT && impliesNotNil(pass, condition.X, object, !negated)
+	case *ast.BinaryExpr:
+		switch condition.Op {
+		case token.LAND, token.LOR:
+			// a && b proves both operands, !(a || b) proves both negations
+			if (condition.Op == token.LAND) != negated {
+				return impliesNotNil(pass, condition.X, object, negated) || impliesNotNil(pass, condition.Y, object, negated)
+			}
+			return false
+		case token.NEQ, token.EQL:
+			if (condition.Op == token.NEQ) == negated {
+				return false
+			}
+			return comparesWithNil(pass, condition.X, condition.Y, object) || comparesWithNil(pass, condition.Y, condition.X, object)
+		}
+	}
+
+	return false
+}
+
+func comparesWithNil(pass *analysis.Pass, variable ast.Expr, value ast.Expr, object types.Object) bool {
+	ident, isIdent := astutil.Unparen(variable).(*ast.Ident)
+	return isIdent && pass.TypesInfo.ObjectOf(ident) == object && pass.TypesInfo.Types[value].IsNil()
+}
+
+// The block always leaves the enclosing block
+func terminates(block *ast.BlockStmt) bool {
+	if len(block.List) == 0 {
+		return false
+	}
+
+	switch last := block.List[len(block.List)-1].(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.BranchStmt:
+		return last.Tok == token.CONTINUE || last.Tok == token.BREAK || last.Tok == token.GOTO
+	case *ast.ExprStmt:
+		call, isCall := last.X.(*ast.CallExpr)
+		if !isCall {
+			return false
+		}
+		ident, isIdent := astutil.Unparen(call.Fun).(*ast.Ident)
+		return isIdent && ident.Name == "panic"
+	}
+
+	return false
+}
+
+func isErrorPointer(typ types.Type) bool {
+	pointer, isPointer := typ.(*types.Pointer)
+	if !isPointer {
+		return false
+	}
+
+	named, isNamed := pointer.Elem().(*types.Named)
+	if !isNamed {
+		return false
+	}
+
+	object := named.Obj()
+	return object.Pkg() != nil && object.Pkg().Path() == gopherpanicPath && object.Name() == "Error"
+}
+
+func isGopherpanicFunc(pass *analysis.Pass, call *ast.CallExpr, name string) bool {
+	var ident *ast.Ident
+	switch fun := astutil.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return false
+	}
+
+	function, isFunc := pass.TypesInfo.Uses[ident].(*types.Func)
+	return isFunc && function.Pkg() != nil && function.Pkg().Path() == gopherpanicPath && function.Name() == name
+}
+
+func sameObject(pass *analysis.Pass, left ast.Expr, right ast.Expr) bool {
+	leftIdent, isLeftIdent := astutil.Unparen(left).(*ast.Ident)
+	rightIdent, isRightIdent := astutil.Unparen(right).(*ast.Ident)
+	if !isLeftIdent || !isRightIdent {
+		return false
+	}
+
+	object := pass.TypesInfo.ObjectOf(leftIdent)
+	return object != nil && object == pass.TypesInfo.ObjectOf(rightIdent)
+}
+
+func constantString(pass *analysis.Pass, expression ast.Expr) *string {
+	typeAndValue, exists := pass.TypesInfo.Types[expression]
+	if !exists || typeAndValue.Value == nil || typeAndValue.Value.Kind() != constant.String {
+		return nil
+	}
+
+	value := constant.StringVal(typeAndValue.Value)
+	return &value
+}
+
+func callName(call *ast.CallExpr) string {
+	switch fun := astutil.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		return fun.Sel.Name
+	default:
+		return "the call"
+	}
+}
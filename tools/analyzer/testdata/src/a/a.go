@@ -0,0 +1,129 @@
+package a
+
+import (
+	"b"
+
+	"github.com/ulphidius/gopherpanic"
+)
+
+func div(x, y int64) (int64, *gopherpanic.Error) {
+	if y == 0 {
+		return 0, gopherpanic.New(gopherpanic.InternalError, "cannot divide by 0")
+	}
+
+	return x / y, nil
+}
+
+func typedNil() error {
+	_, err := div(1, 0)
+	return err // want `typednil: a nil \*gopherpanic.Error converted into error is not a nil interface`
+}
+
+func typedNilAssign() {
+	var result error
+	_, err := div(1, 0)
+	result = err                  // want `typednil`
+	var other error = err         // want `typednil`
+	consume(err)                  // want `typednil`
+	consume(error(err))           // want `typednil`
+	func() error { return err }() // want `typednil`
+	_, _ = result, other
+}
+
+func notNil() error {
+	err := &gopherpanic.Error{}
+	if true {
+		return err
+	}
+	return gopherpanic.New(gopherpanic.InternalError, "sample")
+}
+
+func literal() error {
+	return &gopherpanic.Error{}
+}
+
+func consume(err error) {}
+
+func discarded() {
+	div(1, 0)                                      // want `discarded: the \*gopherpanic.Error returned by div is ignored`
+	gopherpanic.New(gopherpanic.InternalError, "") // want `discarded`
+	_, _ = div(1, 0)                               // want `discarded`
+	result, _ := div(1, 0)                         // want `discarded`
+	_ = result
+}
+
+var sink *gopherpanic.Error
+
+func wrapMessage() {
+	err := gopherpanic.New(gopherpanic.InternalError, "cannot compute")
+	sink = gopherpanic.Wrap(gopherpanic.InternalError, "cannot compute", err)                                      // want `wrapmessage: the Wrap message duplicates the message of the wrapped error`
+	sink = gopherpanic.Wrap(gopherpanic.InternalError, err.Message, err)                                           // want `wrapmessage`
+	sink = gopherpanic.Wrap(gopherpanic.InternalError, "same", gopherpanic.New(gopherpanic.InternalError, "same")) // want `wrapmessage`
+	sink = gopherpanic.Wrap(gopherpanic.InternalError, "fail to fetch statistics data", err)
+	sink = gopherpanic.Wrap(gopherpanic.InternalError, "other", gopherpanic.New(gopherpanic.InternalError, "same"))
+}
+
+func checked() error {
+	if _, err := div(1, 0); err != nil {
+		return err
+	}
+
+	_, err := div(1, 0)
+	if err == nil {
+		return nil
+	}
+	consume(err)
+
+	if _, other := div(1, 0); other == nil {
+		return nil
+	} else if true {
+		return other
+	}
+
+	if _, other := div(1, 0); other != nil || true {
+		return other // want `typednil`
+	}
+
+	return err
+}
+
+func constructed() error {
+	err := gopherpanic.New(gopherpanic.InternalError, "sample")
+	if true {
+		err = gopherpanic.Wrap(gopherpanic.InternalError, "wrapped", err)
+	}
+
+	var literal = &gopherpanic.Error{}
+	consume(literal)
+	return err
+}
+
+func reassigned(result *gopherpanic.Error) error {
+	err := gopherpanic.New(gopherpanic.InternalError, "sample")
+	if true {
+		_, err = div(1, 0)
+	}
+	consume(err) // want `typednil`
+
+	result = gopherpanic.New(gopherpanic.InternalError, "sample")
+	return result // want `typednil`
+}
+
+func newLocal() *gopherpanic.Error { // want newLocal:"neverNil"
+	err := gopherpanic.Error{Message: "local"}
+	return &err
+}
+
+func newLocalWrapper() *gopherpanic.Error { // want newLocalWrapper:"neverNil"
+	return newLocal()
+}
+
+func constructors() error {
+	consume(gopherpanic.NewTemplate(gopherpanic.InternalError, "user {id} not found", gopherpanic.Args{"id": 42}))
+	consume(gopherpanic.NewFromCatalog(gopherpanic.InternalError, nil))
+	consume(gopherpanic.NewValidation(gopherpanic.Violation{Field: "name"}))
+	consume(b.NewUserNotFound(42))
+	consume(newLocalWrapper())
+	consume(b.Lookup("quota")) // want `typednil`
+	return b.NewQuotaExceeded("quota exceeded")
+}
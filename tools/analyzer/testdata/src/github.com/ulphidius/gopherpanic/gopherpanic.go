@@ -0,0 +1,42 @@
+package gopherpanic
+
+type Code struct{}
+
+var InternalError Code
+
+type Args map[string]any
+
+type Violation struct {
+	Field string
+}
+
+type Error struct {
+	Message    string
+	Violations []Violation
+}
+
+func (err Error) Error() string { return err.Message }
+
+func New(code Code, message string) *Error { return &Error{Message: message} }
+
+func Wrap(code Code, message string, err *Error) *Error { return &Error{Message: message} }
+
+func NewTemplate(code Code, template string, args Args) *Error {
+	err := Error{Message: template}
+	return &err
+}
+
+func NewFromCatalog(code Code, args Args) *Error {
+	err := Error{}
+	return &err
+}
+
+func NewValidation(violations ...Violation) *Error {
+	err := newValidation(violations)
+	err.Message = "invalid fields"
+	return err
+}
+
+func newValidation(violations []Violation) *Error {
+	return &Error{Violations: violations}
+}
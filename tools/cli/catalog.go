@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ulphidius/gopherpanic"
+	"gopkg.in/yaml.v3"
+)
+
+// Read a catalog file.
+//
+// Files with the .yaml or .yml extension are decoded as YAML, the others as JSON.
+// Both formats use the same keys as gopherpanic.ParseCatalog.
+func ReadCatalogFile(path string) (*gopherpanic.Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, gopherpanic.New(gopherpanic.IOError, fmt.Sprintf("cannot read catalog %s: %s", path, err))
+	}
+
+	if !isYAML(path) {
+		return gopherpanic.ParseCatalog(data)
+	}
+
+	var content any
+	if err := yaml.Unmarshal(data, &content); err != nil {
+		return nil, gopherpanic.New(gopherpanic.ClientError, fmt.Sprintf("invalid catalog %s: %s", path, err))
+	}
+
+	data, err = json.Marshal(content)
+	if err != nil {
+		return nil, gopherpanic.New(gopherpanic.ClientError, fmt.Sprintf("invalid catalog %s: %s", path, err))
+	}
+
+	return gopherpanic.ParseCatalog(data)
+}
@@ -0,0 +1,206 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/ulphidius/gopherpanic"
+)
+
+// Kind of difference between two versions of a catalog
+type ChangeKind string
+
+const (
+	CodeAdded          ChangeKind = "added"
+	CodeRemoved        ChangeKind = "removed"
+	CodeRenamed        ChangeKind = "renamed"
+	IDChanged          ChangeKind = "id_changed"
+	HTTPStatusChanged  ChangeKind = "http_status_changed"
+	ExitCodeChanged    ChangeKind = "exit_code_changed"
+	DescriptionChanged ChangeKind = "description_changed"
+)
+
+const compatibilityExitError = 2
+
+// Difference between two versions of a code
+type Change struct {
+	Kind     ChangeKind            `json:"kind"`          // Kind of difference
+	Name     string                `json:"name"`          // Name of the code in the old catalog (new catalog for added codes)
+	ID       gopherpanic.ErrorKind `json:"id"`            // ID of the code in the old catalog (new catalog for added codes)
+	Old      string                `json:"old,omitempty"` // Previous value
+	New      string                `json:"new,omitempty"` // Current value
+	Breaking bool                  `json:"breaking"`      // The change breaks the clients of the codes
+}
+
+// Result of a catalog comparison
+type CompatibilityReport struct {
+	Breaking bool     `json:"breaking"` // At least one change is breaking
+	Changes  []Change `json:"changes"`  // Differences ordered by old catalog ID then new catalog ID
+}
+
+// Compare two versions of a catalog.
+//
+// Codes are matched by name. Removed codes, renamed codes and changes of ID, HTTP status or
+// exit code are breaking. Added codes and changes of description are not.
+func CompareCatalogs(old []gopherpanic.CatalogEntry, current []gopherpanic.CatalogEntry) CompatibilityReport {
+	report := CompatibilityReport{Changes: []Change{}}
+	currentByName := map[string]gopherpanic.CatalogEntry{}
+	currentByID := map[gopherpanic.ErrorKind]gopherpanic.CatalogEntry{}
+	oldNames := map[string]bool{}
+
+	for _, entry := range current {
+		currentByName[entry.Name] = entry
+		currentByID[entry.ID] = entry
+	}
+
+	for _, entry := range old {
+		oldNames[entry.Name] = true
+		matched, exists := currentByName[entry.Name]
+		if !exists {
+			if renamed, exists := currentByID[entry.ID]; exists {
+				report.add(Change{Kind: CodeRenamed, Name: entry.Name, ID: entry.ID, Old: entry.Name, New: renamed.Name, Breaking: true})
+				oldNames[renamed.Name] = true
+				continue
+			}
+
+			report.add(Change{Kind: CodeRemoved, Name: entry.Name, ID: entry.ID, Breaking: true})
+			continue
+		}
+
+		if matched.ID != entry.ID {
+			report.add(Change{Kind: IDChanged, Name: entry.Name, ID: entry.ID, Old: formatUint(uint(entry.ID)), New: formatUint(uint(matched.ID)), Breaking: true})
+		}
+
+		if matched.HTTPStatus != entry.HTTPStatus {
+			report.add(Change{Kind: HTTPStatusChanged, Name: entry.Name, ID: entry.ID, Old: strconv.Itoa(entry.HTTPStatus), New: strconv.Itoa(matched.HTTPStatus), Breaking: true})
+		}
+
+		if matched.ExitCode != entry.ExitCode {
+			report.add(Change{Kind: ExitCodeChanged, Name: entry.Name, ID: entry.ID, Old: strconv.Itoa(entry.ExitCode), New: strconv.Itoa(matched.ExitCode), Breaking: true})
+		}
+
+		if matched.Description != entry.Description {
+			report.add(Change{Kind: DescriptionChanged, Name: entry.Name, ID: entry.ID, Old: entry.Description, New: matched.Description})
+		}
+	}
+
+	for _, entry := range current {
+		if !oldNames[entry.Name] {
+			report.add(Change{Kind: CodeAdded, Name: entry.Name, ID: entry.ID})
+		}
+	}
+
+	return report
+}
+
+func (report *CompatibilityReport) add(change Change) {
+	report.Changes = append(report.Changes, change)
+	report.Breaking = report.Breaking || change.Breaking
+}
+
+// Convert into a human readable list of changes
+//
+// breaking: removed: UserNotFound (100)
+//
+// breaking: id_changed: QuotaExceeded (101): "101" -> "102"
+func (report CompatibilityReport) Format() string {
+	result := ""
+	for _, change := range report.Changes {
+		level := "compatible"
+		if change.Breaking {
+			level = "breaking"
+		}
+
+		result += fmt.Sprintf("%s: %s: %s (%d)", level, change.Kind, change.Name, change.ID)
+		if change.Old != "" || change.New != "" {
+			result += fmt.Sprintf(": %q -> %q", change.Old, change.New)
+		}
+		result += "\n"
+	}
+
+	return result
+}
+
+// Convert into JSON string (with or without indentation)
+func (report CompatibilityReport) FormatJSON(indent bool) string {
+	var data []byte
+
+	if indent {
+		data, _ = json.MarshalIndent(report, "", "\t")
+		return string(data)
+	}
+
+	data, _ = json.Marshal(report)
+	return string(data)
+}
+
+// Compare two catalog files (or registry dumps) and report the differences.
+//
+// Usage: compat [-json] <old catalog> <new catalog>
+//
+// Returns 0 without breaking changes, 1 with breaking changes and 2 on failure.
+func Compat(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("compat", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asJSON := flags.Bool("json", false, "print the report in JSON")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: compat [-json] <old catalog> <new catalog>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return compatibilityExitError
+	}
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return compatibilityExitError
+	}
+
+	old, err := ReadCatalogFile(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return compatibilityExitError
+	}
+
+	current, err := ReadCatalogFile(flags.Arg(1))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return compatibilityExitError
+	}
+
+	report := CompareCatalogs(old.Entries(), current.Entries())
+	if *asJSON {
+		fmt.Fprintln(stdout, report.FormatJSON(true))
+	} else {
+		fmt.Fprint(stdout, report.Format())
+	}
+
+	if report.Breaking {
+		return 1
+	}
+
+	return 0
+}
+
+// Print the JSON dump of a catalog, usable as compat input.
+//
+// Usage: dump
+//
+// Returns the process exit code.
+func Dump(catalog *gopherpanic.Catalog, args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) != 0 {
+		fmt.Fprintln(stderr, "usage: dump")
+		return 2
+	}
+
+	fmt.Fprintln(stdout, catalog.FormatJSON(true))
+	return 0
+}
+
+func formatUint(value uint) string {
+	return strconv.FormatUint(uint64(value), 10)
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ulphidius/gopherpanic"
+)
+
+func TestCompareCatalogs(t *testing.T) {
+	type args struct {
+		old     []gopherpanic.CatalogEntry
+		current []gopherpanic.CatalogEntry
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want CompatibilityReport
+	}{
+		{
+			name: "OK - identical",
+			args: args{
+				old:     []gopherpanic.CatalogEntry{{Code: gopherpanic.Code{ID: 100}, Name: "Sample"}},
+				current: []gopherpanic.CatalogEntry{{Code: gopherpanic.Code{ID: 100}, Name: "Sample"}},
+			},
+			want: CompatibilityReport{Changes: []Change{}},
+		},
+		{
+			name: "OK - compatible changes",
+			args: args{
+				old: []gopherpanic.CatalogEntry{{Code: gopherpanic.Code{ID: 100, Description: "old"}, Name: "Sample"}},
+				current: []gopherpanic.CatalogEntry{
+					{Code: gopherpanic.Code{ID: 100, Description: "new"}, Name: "Sample"},
+					{Code: gopherpanic.Code{ID: 101}, Name: "Added"},
+				},
+			},
+			want: CompatibilityReport{
+				Changes: []Change{
+					{Kind: DescriptionChanged, Name: "Sample", ID: 100, Old: "old", New: "new"},
+					{Kind: CodeAdded, Name: "Added", ID: 101},
+				},
+			},
+		},
+		{
+			name: "OK - breaking changes",
+			args: args{
+				old: []gopherpanic.CatalogEntry{
+					{Code: gopherpanic.Code{ID: 100}, Name: "Removed"},
+					{Code: gopherpanic.Code{ID: 101}, Name: "Renamed"},
+					{Code: gopherpanic.Code{ID: 102}, Name: "Moved", HTTPStatus: 404, ExitCode: 1},
+				},
+				current: []gopherpanic.CatalogEntry{
+					{Code: gopherpanic.Code{ID: 101}, Name: "NewName"},
+					{Code: gopherpanic.Code{ID: 103}, Name: "Moved", HTTPStatus: 410, ExitCode: 2},
+				},
+			},
+			want: CompatibilityReport{
+				Breaking: true,
+				Changes: []Change{
+					{Kind: CodeRemoved, Name: "Removed", ID: 100, Breaking: true},
+					{Kind: CodeRenamed, Name: "Renamed", ID: 101, Old: "Renamed", New: "NewName", Breaking: true},
+					{Kind: IDChanged, Name: "Moved", ID: 102, Old: "102", New: "103", Breaking: true},
+					{Kind: HTTPStatusChanged, Name: "Moved", ID: 102, Old: "404", New: "410", Breaking: true},
+					{Kind: ExitCodeChanged, Name: "Moved", ID: 102, Old: "1", New: "2", Breaking: true},
+				},
+			},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := CompareCatalogs(testCase.args.old, testCase.args.current)
+			assert.Equal(t, testCase.want, result)
+		})
+	}
+}
+
+func TestCompat(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		want       int
+		wantStdout string
+	}{
+		{
+			name: "OK - breaking changes",
+			args: []string{filepath.Join("testdata", "errors.yaml"), filepath.Join("testdata", "errors_v2.yaml")},
+			want: 1,
+			wantStdout: "breaking: http_status_changed: UserNotFound (100): \"404\" -> \"410\"\n" +
+				"compatible: description_changed: UserNotFound (100): \"user not found\" -> \"user does not exist\"\n" +
+				"breaking: removed: QuotaExceeded (101)\n" +
+				"compatible: added: Conflict (102)\n",
+		},
+		{
+			name:       "OK - without changes",
+			args:       []string{"-json", filepath.Join("testdata", "errors.yaml"), filepath.Join("testdata", "errors.yaml")},
+			want:       0,
+			wantStdout: "{\n\t\"breaking\": false,\n\t\"changes\": []\n}\n",
+		},
+		{
+			name: "KO - missing file",
+			args: []string{filepath.Join("testdata", "errors.yaml"), filepath.Join("testdata", "missing.yaml")},
+			want: 2,
+		},
+		{
+			name: "KO - missing argument",
+			args: []string{filepath.Join("testdata", "errors.yaml")},
+			want: 2,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			stdout := bytes.Buffer{}
+			result := Compat(testCase.args, &stdout, &bytes.Buffer{})
+			assert.Equal(t, testCase.want, result)
+			assert.Equal(t, testCase.wantStdout, stdout.String())
+		})
+	}
+}
+
+func TestDump(t *testing.T) {
+	catalog := gopherpanic.NewCatalog()
+	catalog.MustRegister(gopherpanic.CatalogEntry{Code: gopherpanic.Code{ID: 100, Description: "sample"}, Name: "Sample"})
+
+	stdout := bytes.Buffer{}
+	result := Dump(catalog, nil, &stdout, &bytes.Buffer{})
+	assert.Equal(t, 0, result)
+
+	dumped, err := gopherpanic.ParseCatalog(stdout.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, catalog.Entries(), dumped.Entries())
+}
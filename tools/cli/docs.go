@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/token"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/ulphidius/gopherpanic"
+)
+
+var markdownFunctions = template.FuncMap{
+	"escape": escapeMarkdown,
+}
+
+var markdownIndexTemplate = template.Must(template.New("index").Funcs(markdownFunctions).Parse(`# Error codes
+
+| ID | Name | Description | HTTP status | Exit code |
+| --- | --- | --- | --- | --- |
+{{- range . }}
+| {{ .ID }} | [{{ .Name }}]({{ .Name }}.md) | {{ escape .Description }} | {{ if .HTTPStatus }}{{ .HTTPStatus }}{{ end }} | {{ if .ExitCode }}{{ .ExitCode }}{{ end }} |
+{{- end }}
+`))
+
+var markdownCodeTemplate = template.Must(template.New("code").Funcs(markdownFunctions).Parse(`# {{ .Name }} ({{ .ID }})
+
+{{ escape .Description }}
+
+| ID | HTTP status | Exit code |
+| --- | --- | --- |
+| {{ .ID }} | {{ if .HTTPStatus }}{{ .HTTPStatus }}{{ end }} | {{ if .ExitCode }}{{ .ExitCode }}{{ end }} |
+{{- if .Explanation }}
+
+## Explanation
+
+{{ escape .Explanation }}
+{{- end }}
+{{- if .Examples }}
+
+## Examples
+{{ range .Examples }}
+- {{ escape . }}
+{{- end }}
+{{- end }}
+{{- if .Remediation }}
+
+## Remediation
+
+{{ escape .Remediation }}
+{{- end }}
+{{- if .DocsURL }}
+
+See: <{{ .DocsURL }}>
+{{- end }}
+
+[Index](index.md)
+`))
+
+var htmlIndexTemplate = htmltemplate.Must(htmltemplate.New("index").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Error codes</title>
+</head>
+<body>
+<h1>Error codes</h1>
+<table>
+<thead>
+<tr><th>ID</th><th>Name</th><th>Description</th><th>HTTP status</th><th>Exit code</th></tr>
+</thead>
+<tbody>
+{{- range . }}
+<tr><td>{{ .ID }}</td><td><a href="{{ .Name }}.html">{{ .Name }}</a></td><td>{{ .Description }}</td><td>{{ if .HTTPStatus }}{{ .HTTPStatus }}{{ end }}</td><td>{{ if .ExitCode }}{{ .ExitCode }}{{ end }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+</body>
+</html>
+`))
+
+var htmlCodeTemplate = htmltemplate.Must(htmltemplate.New("code").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Name }} ({{ .ID }})</title>
+</head>
+<body>
+<h1>{{ .Name }} ({{ .ID }})</h1>
+<p>{{ .Description }}</p>
+<table>
+<tr><th>ID</th><th>HTTP status</th><th>Exit code</th></tr>
+<tr><td>{{ .ID }}</td><td>{{ if .HTTPStatus }}{{ .HTTPStatus }}{{ end }}</td><td>{{ if .ExitCode }}{{ .ExitCode }}{{ end }}</td></tr>
+</table>
+{{- if .Explanation }}
+<h2>Explanation</h2>
+<p>{{ .Explanation }}</p>
+{{- end }}
+{{- if .Examples }}
+<h2>Examples</h2>
+<ul>
+{{- range .Examples }}
+<li><code>{{ . }}</code></li>
+{{- end }}
+</ul>
+{{- end }}
+{{- if .Remediation }}
+<h2>Remediation</h2>
+<p>{{ .Remediation }}</p>
+{{- end }}
+{{- if .DocsURL }}
+<p>See: <a href="{{ .DocsURL }}">{{ .DocsURL }}</a></p>
+{{- end }}
+<p><a href="index.html">Index</a></p>
+</body>
+</html>
+`))
+
+type executor interface {
+	Execute(writer io.Writer, data any) error
+}
+
+// Generate the documentation pages of a catalog.
+//
+// Usage: docs [-catalog file] [-format markdown|html] [-output directory]
+//
+// The registered codes (DefaultCatalog for the gopherpanic command) are documented
+// unless -catalog is given. An index page and a page per code are written in the output directory.
+// Returns the process exit code.
+func Docs(catalog *gopherpanic.Catalog, args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("docs", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	catalogPath := flags.String("catalog", "", "YAML or JSON catalog file used instead of the registered codes")
+	docFormat := flags.String("format", "markdown", "output format: markdown or html")
+	output := flags.String("output", "docs", "output directory")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: docs [-catalog file] [-format markdown|html] [-output directory]")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
+	if *catalogPath != "" {
+		loaded, err := ReadCatalogFile(*catalogPath)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		catalog = loaded
+	}
+
+	files, err := GenerateDocs(catalog.Entries(), *docFormat)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	if err := os.MkdirAll(*output, 0o755); err != nil {
+		fmt.Fprintln(stderr, gopherpanic.New(gopherpanic.IOError, fmt.Sprintf("cannot create %s: %s", *output, err)).Format(false, false))
+		return 1
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(*output, name)
+		if err := os.WriteFile(path, files[name], 0o644); err != nil {
+			fmt.Fprintln(stderr, gopherpanic.New(gopherpanic.IOError, fmt.Sprintf("cannot write %s: %s", path, err)).Format(false, false))
+			return 1
+		}
+		fmt.Fprintln(stdout, path)
+	}
+
+	return 0
+}
+
+// Render the documentation pages of the entries indexed by file name.
+//
+// Allowed formats:
+//
+// - markdown: index.md and <Name>.md
+//
+// - html: index.html and <Name>.html
+//
+// Fails if a name is not an exported Go identifier or is the reserved index name.
+func GenerateDocs(entries []gopherpanic.CatalogEntry, docFormat string) (map[string][]byte, error) {
+	var index, code executor
+	var extension string
+
+	switch docFormat {
+	case "markdown", "md":
+		index, code, extension = markdownIndexTemplate, markdownCodeTemplate, ".md"
+	case "html":
+		index, code, extension = htmlIndexTemplate, htmlCodeTemplate, ".html"
+	default:
+		return nil, gopherpanic.New(gopherpanic.ClientError, fmt.Sprintf("unknown documentation format %q", docFormat))
+	}
+
+	for _, entry := range entries {
+		if !token.IsIdentifier(entry.Name) || !unicode.IsUpper([]rune(entry.Name)[0]) {
+			return nil, gopherpanic.New(gopherpanic.ClientError, fmt.Sprintf("code name %q is not an exported Go identifier", entry.Name))
+		}
+
+		// Case-insensitive file systems would overwrite the index page with Index
+		if strings.EqualFold(entry.Name, "index") {
+			return nil, gopherpanic.New(gopherpanic.ClientError, fmt.Sprintf("code name %q is reserved by the index page", entry.Name))
+		}
+	}
+
+	files := map[string][]byte{}
+	buffer := bytes.Buffer{}
+	if err := index.Execute(&buffer, entries); err != nil {
+		return nil, gopherpanic.New(gopherpanic.InternalError, fmt.Sprintf("cannot render index: %s", err))
+	}
+	files["index"+extension] = append([]byte(nil), buffer.Bytes()...)
+
+	for _, entry := range entries {
+		buffer.Reset()
+		if err := code.Execute(&buffer, entry); err != nil {
+			return nil, gopherpanic.New(gopherpanic.InternalError, fmt.Sprintf("cannot render %s: %s", entry.Name, err))
+		}
+		files[entry.Name+extension] = append([]byte(nil), buffer.Bytes()...)
+	}
+
+	return files, nil
+}
+
+func escapeMarkdown(text string) string {
+	return strings.NewReplacer(
+		"\\", "\\\\",
+		"|", "\\|",
+		"*", "\\*",
+		"_", "\\_",
+		"`", "\\`",
+		"<", "&lt;",
+		">", "&gt;",
+		"\n", " ",
+	).Replace(text)
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ulphidius/gopherpanic"
+)
+
+func TestGenerateDocs(t *testing.T) {
+	entries := []gopherpanic.CatalogEntry{
+		{
+			Code:        gopherpanic.Code{ID: 100, Description: "user <id> not found"},
+			Name:        "UserNotFound",
+			Explanation: "the user | does not exist",
+			Examples:    []string{"GET /users/42"},
+			HTTPStatus:  404,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		args    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "OK - markdown",
+			args: "markdown",
+			want: map[string]string{
+				"index.md":        "# Error codes\n\n| ID | Name | Description | HTTP status | Exit code |\n| --- | --- | --- | --- | --- |\n| 100 | [UserNotFound](UserNotFound.md) | user &lt;id&gt; not found | 404 |  |\n",
+				"UserNotFound.md": "# UserNotFound (100)\n\nuser &lt;id&gt; not found\n\n| ID | HTTP status | Exit code |\n| --- | --- | --- |\n| 100 | 404 |  |\n\n## Explanation\n\nthe user \\| does not exist\n\n## Examples\n\n- GET /users/42\n\n[Index](index.md)\n",
+			},
+		},
+		{
+			name:    "KO - unknown format",
+			args:    "pdf",
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := GenerateDocs(entries, testCase.args)
+			assert.Equal(t, testCase.wantErr, err != nil)
+			for name, content := range testCase.want {
+				assert.Equal(t, content, string(result[name]))
+			}
+		})
+	}
+}
+
+func TestGenerateDocsHTML(t *testing.T) {
+	entries := []gopherpanic.CatalogEntry{{Code: gopherpanic.Code{ID: 100, Description: "user <id> not found"}, Name: "UserNotFound"}}
+
+	result, err := GenerateDocs(entries, "html")
+	assert.Nil(t, err)
+	assert.Contains(t, string(result["index.html"]), `<tr><td>100</td><td><a href="UserNotFound.html">UserNotFound</a></td><td>user &lt;id&gt; not found</td><td></td><td></td></tr>`)
+	assert.Contains(t, string(result["UserNotFound.html"]), "<h1>UserNotFound (100)</h1>\n<p>user &lt;id&gt; not found</p>")
+}
+
+func TestGenerateDocsInvalidName(t *testing.T) {
+	for _, name := range []string{"../../UserNotFound", "user_not_found", "", "Index"} {
+		t.Run(name, func(t *testing.T) {
+			_, err := GenerateDocs([]gopherpanic.CatalogEntry{{Code: gopherpanic.Code{ID: 100}, Name: name}}, "markdown")
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestDocs(t *testing.T) {
+	output := t.TempDir()
+
+	tests := []struct {
+		name      string
+		args      []string
+		want      int
+		wantFiles []string
+	}{
+		{
+			name:      "OK - catalog file",
+			args:      []string{"-catalog", filepath.Join("testdata", "errors.yaml"), "-output", filepath.Join(output, "catalog")},
+			want:      0,
+			wantFiles: []string{"QuotaExceeded.md", "UserNotFound.md", "index.md"},
+		},
+		{
+			name:      "OK - registered codes",
+			args:      []string{"-format", "html", "-output", filepath.Join(output, "registered")},
+			want:      0,
+			wantFiles: []string{"CircuitOpenError.html", "ClientError.html", "IOError.html", "InternalError.html", "NetworkError.html", "TimeoutError.html", "UnauthorizedError.html", "UnimplementedError.html", "UnknownError.html", "index.html"},
+		},
+		{
+			name: "KO - unknown format",
+			args: []string{"-format", "pdf", "-output", filepath.Join(output, "pdf")},
+			want: 1,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := Docs(gopherpanic.DefaultCatalog, testCase.args, &bytes.Buffer{}, &bytes.Buffer{})
+			assert.Equal(t, testCase.want, result)
+			if testCase.wantFiles == nil {
+				return
+			}
+
+			files, err := os.ReadDir(testCase.args[len(testCase.args)-1])
+			assert.Nil(t, err)
+			names := []string{}
+			for _, file := range files {
+				names = append(names, file.Name())
+			}
+			assert.Equal(t, testCase.wantFiles, names)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Package cli contains the gopherpanic subcommands.
+//
+// Each subcommand is exposed as a function so other binaries can provide the same behavior
+// with their own catalog.
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/ulphidius/gopherpanic"
+)
+
+// Print the long-form explanation of a code registered in the catalog.
+//
+// Usage: explain [-catalog file] <code id or name>
+//
+// The -catalog flag replaces the given catalog by the content of the file.
+// Returns the process exit code.
+func Explain(catalog *gopherpanic.Catalog, args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("explain", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	catalogPath := flags.String("catalog", "", "YAML or JSON catalog file used instead of the embedded catalog")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: explain [-catalog file] <code id or name>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	if *catalogPath != "" {
+		loaded, err := ReadCatalogFile(*catalogPath)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		catalog = loaded
+	}
+
+	entry, exists := catalog.Lookup(flags.Arg(0))
+	if !exists {
+		fmt.Fprintln(stderr, gopherpanic.New(gopherpanic.ClientError, fmt.Sprintf("unknown code %s", flags.Arg(0))).Format(false, false))
+		return 1
+	}
+
+	fmt.Fprint(stdout, entry.Explain())
+	return 0
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ulphidius/gopherpanic"
+)
+
+func TestExplain(t *testing.T) {
+	dir := t.TempDir()
+	catalogPath := filepath.Join(dir, "catalog.json")
+	assert.Nil(t, os.WriteFile(catalogPath, []byte(`{"codes":[{"id":100,"name":"UserNotFound","description":"user not found"}]}`), 0o600))
+
+	tests := []struct {
+		name       string
+		args       []string
+		want       int
+		wantStdout string
+	}{
+		{
+			name:       "OK - builtin code by name",
+			args:       []string{"UnimplementedError"},
+			want:       0,
+			wantStdout: "UnimplementedError (7): unimplemented behavior\n\nExplanation:\n\tThe requested behavior exists in the API but is not implemented yet.\n\nRemediation:\n\tAvoid calling the behavior or implement it.\n",
+		},
+		{
+			name:       "OK - catalog file",
+			args:       []string{"-catalog", catalogPath, "100"},
+			want:       0,
+			wantStdout: "UserNotFound (100): user not found\n",
+		},
+		{
+			name: "KO - unknown code",
+			args: []string{"100"},
+			want: 1,
+		},
+		{
+			name: "KO - missing catalog file",
+			args: []string{"-catalog", filepath.Join(dir, "missing.json"), "100"},
+			want: 1,
+		},
+		{
+			name: "KO - missing argument",
+			args: []string{},
+			want: 2,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			stdout := bytes.Buffer{}
+			result := Explain(gopherpanic.DefaultCatalog, testCase.args, &stdout, &bytes.Buffer{})
+			assert.Equal(t, testCase.want, result)
+			assert.Equal(t, testCase.wantStdout, stdout.String())
+		})
+	}
+}
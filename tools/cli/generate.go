@@ -0,0 +1,263 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ulphidius/gopherpanic"
+)
+
+type templateParam struct {
+	Name     string // Placeholder name
+	Variable string // Parameter name of the generated constructor
+}
+
+type generatedEntry struct {
+	gopherpanic.CatalogEntry
+	Params []templateParam
+}
+
+var codeTemplate = template.Must(template.New("code").Parse(`// Code generated by gopherpanic generate; DO NOT EDIT.
+
+package {{ .Package }}
+
+import "github.com/ulphidius/gopherpanic"
+
+const (
+{{- range .Entries }}
+	{{ .Name }}Kind gopherpanic.ErrorKind = {{ .ID }}
+{{- end }}
+)
+
+var (
+{{- range .Entries }}
+	{{ .Name }} gopherpanic.Code = gopherpanic.Code{
+		ID:          {{ .Name }}Kind,
+		Description: {{ printf "%q" .Description }},
+	}
+{{- end }}
+)
+
+func init() {
+	gopherpanic.MustRegister(
+{{- range .Entries }}
+		gopherpanic.CatalogEntry{
+			Code:        {{ .Name }},
+			Name:        {{ printf "%q" .Name }},
+{{- if .Explanation }}
+			Explanation: {{ printf "%q" .Explanation }},
+{{- end }}
+{{- if .Examples }}
+			Examples:    []string{ {{- range $index, $example := .Examples }}{{ if $index }}, {{ end }}{{ printf "%q" $example }}{{ end -}} },
+{{- end }}
+{{- if .Remediation }}
+			Remediation: {{ printf "%q" .Remediation }},
+{{- end }}
+{{- if .HTTPStatus }}
+			HTTPStatus:  {{ .HTTPStatus }},
+{{- end }}
+{{- if .ExitCode }}
+			ExitCode:    {{ .ExitCode }},
+{{- end }}
+{{- if .DocsURL }}
+			DocsURL:     {{ printf "%q" .DocsURL }},
+{{- end }}
+{{- if .Template }}
+			Template:    {{ printf "%q" .Template }},
+{{- end }}
+{{- if .Retryable }}
+			Retryable:   true,
+{{- end }}
+		},
+{{- end }}
+	)
+}
+{{ range .Entries }}
+{{- if .Template }}
+// Create a new {{ .Name }} error with the message template {{ printf "%q" .Template }}
+func New{{ .Name }}({{ range $index, $param := .Params }}{{ if $index }}, {{ end }}{{ $param.Variable }} any{{ end }}) *gopherpanic.Error {
+	err := gopherpanic.ErrorBuilder{}.New().
+		WithCode({{ .Name }}).
+		WithTemplate({{ printf "%q" .Template }}, gopherpanic.Args{
+{{- range .Params }}
+			{{ printf "%q" .Name }}: {{ .Variable }},
+{{- end }}
+		}).
+		WithPosition(gopherpanic.Position{}.SpawnCaller(1)).
+		Build()
+	return &err
+}
+{{ else }}
+// Create a new {{ .Name }} error
+func New{{ .Name }}(message string, traces ...gopherpanic.Trace) *gopherpanic.Error {
+	err := gopherpanic.ErrorBuilder{}.New().
+		WithCode({{ .Name }}).
+		WithMessage(message).
+		WithPosition(gopherpanic.Position{}.SpawnCaller(1)).
+		WithTraces(traces...).
+		Build()
+	return &err
+}
+{{ end }}
+{{- end }}`))
+
+// Generate Go code from a catalog file.
+//
+// Usage: generate -catalog errors.yaml [-package name] [-output file.go]
+//
+// Designed to be used with go generate:
+//
+//	//go:generate go run github.com/ulphidius/gopherpanic/tools/cmd/gopherpanic generate -catalog errors.yaml -output errors_gen.go
+//
+// Returns the process exit code.
+func Generate(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	catalogPath := flags.String("catalog", "", "YAML or JSON catalog file")
+	packageName := flags.String("package", os.Getenv("GOPACKAGE"), "package of the generated file (default $GOPACKAGE)")
+	output := flags.String("output", "", "generated file (default stdout)")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: generate -catalog errors.yaml [-package name] [-output file.go]")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *catalogPath == "" || *packageName == "" || flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
+	catalog, err := ReadCatalogFile(*catalogPath)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	code, err := GenerateCode(catalog.Entries(), *packageName)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	if *output == "" {
+		_, _ = stdout.Write(code)
+		return 0
+	}
+
+	if err := os.WriteFile(*output, code, 0o644); err != nil {
+		fmt.Fprintln(stderr, gopherpanic.New(gopherpanic.IOError, fmt.Sprintf("cannot write %s: %s", *output, err)).Format(false, false))
+		return 1
+	}
+
+	return 0
+}
+
+// Generate the Go source declaring the constants, codes, registration and constructors of the entries.
+//
+// Fails if an ID or a name is duplicated, already used by the DefaultCatalog (builtin codes),
+// if a name is not an exported Go identifier or if the generated identifiers (<Name>, <Name>Kind
+// and New<Name>) collide.
+func GenerateCode(entries []gopherpanic.CatalogEntry, packageName string) ([]byte, error) {
+	if !token.IsIdentifier(packageName) {
+		return nil, gopherpanic.New(gopherpanic.ClientError, fmt.Sprintf("invalid package name %q", packageName))
+	}
+
+	catalog := gopherpanic.NewCatalog()
+	if err := catalog.Register(entries...); err != nil {
+		return nil, err
+	}
+
+	generated := []generatedEntry{}
+	identifiers := map[string]string{}
+	for _, entry := range catalog.Entries() {
+		if !token.IsIdentifier(entry.Name) || !unicode.IsUpper([]rune(entry.Name)[0]) {
+			return nil, gopherpanic.New(gopherpanic.ClientError, fmt.Sprintf("code name %q is not an exported Go identifier", entry.Name))
+		}
+
+		if registered, exists := gopherpanic.DefaultCatalog.LookupCode(entry.Code); exists {
+			return nil, gopherpanic.New(gopherpanic.ClientError, fmt.Sprintf("code id %d is already used by %s", entry.ID, registered.Name))
+		}
+
+		if registered, exists := gopherpanic.Lookup(entry.Name); exists {
+			return nil, gopherpanic.New(gopherpanic.ClientError, fmt.Sprintf("code name %s is already used by code id %d", entry.Name, registered.ID))
+		}
+
+		for _, identifier := range []string{entry.Name, entry.Name + "Kind", "New" + entry.Name} {
+			if other, exists := identifiers[identifier]; exists {
+				return nil, gopherpanic.New(gopherpanic.ClientError, fmt.Sprintf("code names %s and %s both generate %s", other, entry.Name, identifier))
+			}
+			identifiers[identifier] = entry.Name
+		}
+
+		generated = append(generated, generatedEntry{CatalogEntry: entry, Params: templateParams(entry.Template)})
+	}
+
+	buffer := bytes.Buffer{}
+	err := codeTemplate.Execute(&buffer, struct {
+		Package string
+		Entries []generatedEntry
+	}{
+		Package: packageName,
+		Entries: generated,
+	})
+	if err != nil {
+		return nil, gopherpanic.New(gopherpanic.InternalError, fmt.Sprintf("cannot generate code: %s", err))
+	}
+
+	code, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return nil, gopherpanic.New(gopherpanic.InternalError, fmt.Sprintf("cannot format generated code: %s", err))
+	}
+
+	return code, nil
+}
+
+// Identifiers used by the body of the generated constructors
+var reservedParams = map[string]bool{"err": true, "gopherpanic": true}
+
+// Convert the template placeholders into constructor parameters (user_id -> userID)
+//
+// The placeholders which do not give a free identifier are named arg<index>.
+func templateParams(template string) []templateParam {
+	params := []templateParam{}
+	variables := map[string]bool{}
+	for _, name := range gopherpanic.TemplatePlaceholders(template) {
+		variable := ""
+		for index, word := range strings.FieldsFunc(name, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+			first, size := utf8.DecodeRuneInString(word)
+			switch {
+			case index == 0:
+				variable += string(unicode.ToLower(first)) + word[size:]
+			case strings.ToLower(word) == "id" || strings.ToLower(word) == "url":
+				variable += strings.ToUpper(word)
+			default:
+				variable += string(unicode.ToUpper(first)) + word[size:]
+			}
+		}
+
+		for index := len(params); !token.IsIdentifier(variable) || variables[variable] || reservedParams[variable]; index++ {
+			variable = fmt.Sprintf("arg%d", index)
+		}
+
+		variables[variable] = true
+		params = append(params, templateParam{Name: name, Variable: variable})
+	}
+
+	return params
+}
+
+func isYAML(path string) bool {
+	return strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml")
+}
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ulphidius/gopherpanic"
+)
+
+func TestGenerate(t *testing.T) {
+	golden, err := os.ReadFile(filepath.Join("testdata", "errors_gen.go.golden"))
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name       string
+		args       []string
+		want       int
+		wantStdout string
+	}{
+		{
+			name:       "OK",
+			args:       []string{"-catalog", filepath.Join("testdata", "errors.yaml"), "-package", "errs"},
+			want:       0,
+			wantStdout: string(golden),
+		},
+		{
+			name: "KO - missing catalog",
+			args: []string{"-package", "errs"},
+			want: 2,
+		},
+		{
+			name: "KO - unknown catalog file",
+			args: []string{"-catalog", filepath.Join("testdata", "missing.yaml"), "-package", "errs"},
+			want: 1,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			stdout := bytes.Buffer{}
+			result := Generate(testCase.args, &stdout, &bytes.Buffer{})
+			assert.Equal(t, testCase.want, result)
+			assert.Equal(t, testCase.wantStdout, stdout.String())
+		})
+	}
+}
+
+func TestGenerateCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []gopherpanic.CatalogEntry
+		pkg     string
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			args: []gopherpanic.CatalogEntry{{Code: gopherpanic.Code{ID: 100}, Name: "Sample"}},
+			pkg:  "errs",
+		},
+		{
+			name:    "KO - duplicated id",
+			args:    []gopherpanic.CatalogEntry{{Code: gopherpanic.Code{ID: 100}, Name: "First"}, {Code: gopherpanic.Code{ID: 100}, Name: "Second"}},
+			pkg:     "errs",
+			wantErr: true,
+		},
+		{
+			name:    "KO - duplicated name",
+			args:    []gopherpanic.CatalogEntry{{Code: gopherpanic.Code{ID: 100}, Name: "Sample"}, {Code: gopherpanic.Code{ID: 101}, Name: "Sample"}},
+			pkg:     "errs",
+			wantErr: true,
+		},
+		{
+			name:    "KO - builtin id",
+			args:    []gopherpanic.CatalogEntry{{Code: gopherpanic.Code{ID: 3}, Name: "Sample"}},
+			pkg:     "errs",
+			wantErr: true,
+		},
+		{
+			name:    "KO - builtin name",
+			args:    []gopherpanic.CatalogEntry{{Code: gopherpanic.Code{ID: 100}, Name: "InternalError"}},
+			pkg:     "errs",
+			wantErr: true,
+		},
+		{
+			name:    "KO - unexported name",
+			args:    []gopherpanic.CatalogEntry{{Code: gopherpanic.Code{ID: 100}, Name: "sample"}},
+			pkg:     "errs",
+			wantErr: true,
+		},
+		{
+			name:    "KO - generated identifier collision",
+			args:    []gopherpanic.CatalogEntry{{Code: gopherpanic.Code{ID: 100}, Name: "User"}, {Code: gopherpanic.Code{ID: 101}, Name: "UserKind"}},
+			pkg:     "errs",
+			wantErr: true,
+		},
+		{
+			name:    "KO - invalid package",
+			args:    []gopherpanic.CatalogEntry{{Code: gopherpanic.Code{ID: 100}, Name: "Sample"}},
+			pkg:     "my-errs",
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := GenerateCode(testCase.args, testCase.pkg)
+			assert.Equal(t, testCase.wantErr, err != nil)
+			assert.Equal(t, testCase.wantErr, result == nil)
+		})
+	}
+}
+
+func TestTemplateParams(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want []templateParam
+	}{
+		{
+			name: "OK - camel case",
+			args: "user {user_id} not found at {docs-url}",
+			want: []templateParam{{Name: "user_id", Variable: "userID"}, {Name: "docs-url", Variable: "docsURL"}},
+		},
+		{
+			name: "OK - reserved names",
+			args: "{gopherpanic} {err}",
+			want: []templateParam{{Name: "gopherpanic", Variable: "arg0"}, {Name: "err", Variable: "arg1"}},
+		},
+		{
+			name: "OK - multibyte first rune",
+			args: "{Été} {nom_élève}",
+			want: []templateParam{{Name: "Été", Variable: "été"}, {Name: "nom_élève", Variable: "nomÉlève"}},
+		},
+		{
+			name: "OK - fallback already used",
+			args: "{arg1} {1st}",
+			want: []templateParam{{Name: "arg1", Variable: "arg1"}, {Name: "1st", Variable: "arg2"}},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, templateParams(testCase.args))
+		})
+	}
+}
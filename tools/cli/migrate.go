@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ulphidius/gopherpanic"
+	"github.com/ulphidius/gopherpanic/tools/migrate"
+)
+
+// Rewrite the errors.New and fmt.Errorf calls of Go packages into gopherpanic errors.
+//
+// Usage: migrate [-w] [-code InternalError] [-infer=false] <packages>
+//
+// Packages are directories, the /... suffix includes the sub-directories (testdata, vendor and
+// hidden directories excluded). Without -w the unified diff of the changes is printed.
+// Returns the process exit code.
+func Migrate(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	write := flags.Bool("w", false, "write the changes into the files instead of printing the diff")
+	code := flags.String("code", "InternalError", "gopherpanic Code used when no code is inferred")
+	infer := flags.Bool("infer", true, "infer the Code from the message keywords")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: migrate [-w] [-code InternalError] [-infer=false] <packages>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	files, err := goFiles(flags.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	status := 0
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintln(stderr, gopherpanic.New(gopherpanic.IOError, fmt.Sprintf("cannot read %s: %s", file, err)).Format(false, false))
+			status = 1
+			continue
+		}
+
+		result, err := migrate.File(file, src, migrate.Options{Code: *code, Infer: *infer})
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			status = 1
+			continue
+		}
+
+		for _, skipped := range result.Skipped {
+			fmt.Fprintf(stderr, "%s: skipped: the call cannot be rewritten automatically\n", skipped)
+		}
+
+		if result.Rewrites == 0 {
+			continue
+		}
+
+		if !*write {
+			fmt.Fprint(stdout, migrate.Diff(file, src, result.Source))
+			continue
+		}
+
+		if err := os.WriteFile(file, result.Source, 0o644); err != nil {
+			fmt.Fprintln(stderr, gopherpanic.New(gopherpanic.IOError, fmt.Sprintf("cannot write %s: %s", file, err)).Format(false, false))
+			status = 1
+		}
+	}
+
+	return status
+}
+
+// List the Go files of the packages
+func goFiles(patterns []string) ([]string, error) {
+	files := []string{}
+	for _, pattern := range patterns {
+		if strings.HasSuffix(pattern, ".go") {
+			files = append(files, pattern)
+			continue
+		}
+
+		root, recursive := strings.CutSuffix(pattern, "/...")
+		if root == "" {
+			root = "."
+		}
+
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if entry.IsDir() {
+				name := entry.Name()
+				if path != root && (!recursive || name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if strings.HasSuffix(path, ".go") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, gopherpanic.New(gopherpanic.IOError, fmt.Sprintf("cannot list %s: %s", pattern, err))
+		}
+	}
+
+	return files, nil
+}
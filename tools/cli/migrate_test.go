@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrate(t *testing.T) {
+	source := "package a\n\nimport \"errors\"\n\nvar ErrSample = errors.New(\"sample\")\n"
+	migrated := "package a\n\nimport \"github.com/ulphidius/gopherpanic\"\n\nvar ErrSample = error(gopherpanic.New(gopherpanic.InternalError, \"sample\"))\n"
+
+	tests := []struct {
+		name       string
+		args       func(dir string) []string
+		want       int
+		wantStdout func(dir string) string
+		wantFile   string
+	}{
+		{
+			name: "OK - dry run",
+			args: func(dir string) []string { return []string{dir + "/..."} },
+			want: 0,
+			wantStdout: func(dir string) string {
+				file := filepath.Join(dir, "sub", "a.go")
+				return "--- " + file + "\n+++ " + file + "\n@@ -1,5 +1,5 @@\n package a\n \n-import \"errors\"\n+import \"github.com/ulphidius/gopherpanic\"\n \n-var ErrSample = errors.New(\"sample\")\n+var ErrSample = error(gopherpanic.New(gopherpanic.InternalError, \"sample\"))\n"
+			},
+			wantFile: source,
+		},
+		{
+			name:       "OK - not recursive",
+			args:       func(dir string) []string { return []string{dir} },
+			want:       0,
+			wantStdout: func(dir string) string { return "" },
+			wantFile:   source,
+		},
+		{
+			name:       "OK - write",
+			args:       func(dir string) []string { return []string{"-w", dir + "/..."} },
+			want:       0,
+			wantStdout: func(dir string) string { return "" },
+			wantFile:   migrated,
+		},
+		{
+			name:       "KO - missing directory",
+			args:       func(dir string) []string { return []string{filepath.Join(dir, "missing")} },
+			want:       1,
+			wantStdout: func(dir string) string { return "" },
+			wantFile:   source,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			dir := t.TempDir()
+			file := filepath.Join(dir, "sub", "a.go")
+			assert.Nil(t, os.MkdirAll(filepath.Dir(file), 0o755))
+			assert.Nil(t, os.WriteFile(file, []byte(source), 0o644))
+
+			stdout := bytes.Buffer{}
+			result := Migrate(testCase.args(dir), &stdout, &bytes.Buffer{})
+			assert.Equal(t, testCase.want, result)
+			assert.Equal(t, testCase.wantStdout(dir), stdout.String())
+
+			content, err := os.ReadFile(file)
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.wantFile, string(content))
+		})
+	}
+}
@@ -0,0 +1,16 @@
+// Command gopherpanic-vet runs the gopherpanic analyzer.
+//
+// Usage:
+//
+//	gopherpanic-vet ./...
+//	go vet -vettool=$(which gopherpanic-vet) ./...
+package main
+
+import (
+	"github.com/ulphidius/gopherpanic/tools/analyzer"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
@@ -0,0 +1,60 @@
+// Command gopherpanic provides tools around gopherpanic error codes.
+//
+// Usage:
+//
+//	gopherpanic explain [-catalog file] <code id or name>
+//	gopherpanic generate -catalog errors.yaml [-package name] [-output file.go]
+//	gopherpanic docs [-catalog file] [-format markdown|html] [-output directory]
+//	gopherpanic compat [-json] <old catalog> <new catalog>
+//	gopherpanic dump
+//	gopherpanic migrate [-w] [-code InternalError] [-infer=false] <packages>
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ulphidius/gopherpanic"
+	"github.com/ulphidius/gopherpanic/tools/cli"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	if len(args) == 0 {
+		usage()
+		return 2
+	}
+
+	switch args[0] {
+	case "explain":
+		return cli.Explain(gopherpanic.DefaultCatalog, args[1:], os.Stdout, os.Stderr)
+	case "generate":
+		return cli.Generate(args[1:], os.Stdout, os.Stderr)
+	case "docs":
+		return cli.Docs(gopherpanic.DefaultCatalog, args[1:], os.Stdout, os.Stderr)
+	case "compat":
+		return cli.Compat(args[1:], os.Stdout, os.Stderr)
+	case "dump":
+		return cli.Dump(gopherpanic.DefaultCatalog, args[1:], os.Stdout, os.Stderr)
+	case "migrate":
+		return cli.Migrate(args[1:], os.Stdout, os.Stderr)
+	default:
+		usage()
+		return 2
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: gopherpanic <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "\texplain\tprint the explanation of an error code")
+	fmt.Fprintln(os.Stderr, "\tgenerate\tgenerate Go code from a catalog file")
+	fmt.Fprintln(os.Stderr, "\tdocs\tgenerate the Markdown or HTML documentation of the error codes")
+	fmt.Fprintln(os.Stderr, "\tcompat\treport the breaking changes between two catalogs")
+	fmt.Fprintln(os.Stderr, "\tdump\tprint the registered codes as a JSON catalog")
+	fmt.Fprintln(os.Stderr, "\tmigrate\trewrite errors.New and fmt.Errorf calls into gopherpanic errors")
+}
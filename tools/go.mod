@@ -0,0 +1,22 @@
+module github.com/ulphidius/gopherpanic/tools
+
+go 1.22.0
+
+require (
+	github.com/stretchr/testify v1.8.2
+	github.com/ulphidius/gopherpanic v0.0.0-00010101000000-000000000000
+	golang.org/x/tools v0.26.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/ulphidius/iterago v0.7.0 // indirect
+	golang.org/x/mod v0.21.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+)
+
+// The tools are built against the library of the same commit, the directive is replaced by the
+// released library version when the tools module is tagged.
+replace github.com/ulphidius/gopherpanic => ../
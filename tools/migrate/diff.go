@@ -0,0 +1,165 @@
+package migrate
+
+import (
+	"fmt"
+	"strings"
+)
+
+const diffContext = 3
+
+type diffLine struct {
+	kind byte // ' ', '-' or '+'
+	text string
+	old  int // line number in the old text (0 for added lines)
+	new  int // line number in the new text (0 for removed lines)
+}
+
+// Compute the unified diff between two versions of a file.
+//
+// Returns an empty string when the versions are identical.
+func Diff(name string, old []byte, current []byte) string {
+	oldLines := splitLines(string(old))
+	newLines := splitLines(string(current))
+	lines := diffLines(oldLines, newLines)
+
+	changed := false
+	for _, line := range lines {
+		changed = changed || line.kind != ' '
+	}
+
+	if !changed {
+		return ""
+	}
+
+	builder := strings.Builder{}
+	fmt.Fprintf(&builder, "--- %s\n+++ %s\n", name, name)
+
+	for start := 0; start < len(lines); {
+		for start < len(lines) && lines[start].kind == ' ' {
+			start++
+		}
+
+		if start == len(lines) {
+			break
+		}
+
+		first := max(start-diffContext, 0)
+		end := start
+		for end < len(lines) {
+			next := end
+			for next < len(lines) && lines[next].kind == ' ' {
+				next++
+			}
+
+			if next == len(lines) || next-end > 2*diffContext {
+				end = min(end+diffContext, len(lines))
+				break
+			}
+
+			for next < len(lines) && lines[next].kind != ' ' {
+				next++
+			}
+			end = next
+		}
+
+		writeHunk(&builder, lines[first:end])
+		start = end
+	}
+
+	return builder.String()
+}
+
+func writeHunk(builder *strings.Builder, hunk []diffLine) {
+	oldStart, oldCount, newStart, newCount := 0, 0, 0, 0
+	for _, line := range hunk {
+		if line.kind != '+' {
+			if oldStart == 0 {
+				oldStart = line.old
+			}
+			oldCount++
+		}
+
+		if line.kind != '-' {
+			if newStart == 0 {
+				newStart = line.new
+			}
+			newCount++
+		}
+	}
+
+	fmt.Fprintf(builder, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+	for _, line := range hunk {
+		fmt.Fprintf(builder, "%c%s\n", line.kind, line.text)
+	}
+}
+
+// Longest common subsequence diff, the common prefix and suffix are skipped
+func diffLines(old []string, current []string) []diffLine {
+	prefix := 0
+	for prefix < len(old) && prefix < len(current) && old[prefix] == current[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(old)-prefix && suffix < len(current)-prefix && old[len(old)-1-suffix] == current[len(current)-1-suffix] {
+		suffix++
+	}
+
+	oldMiddle := old[prefix : len(old)-suffix]
+	newMiddle := current[prefix : len(current)-suffix]
+
+	lengths := make([][]int, len(oldMiddle)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(newMiddle)+1)
+	}
+
+	for i := len(oldMiddle) - 1; i >= 0; i-- {
+		for j := len(newMiddle) - 1; j >= 0; j-- {
+			if oldMiddle[i] == newMiddle[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]diffLine, 0, len(old)+len(current))
+	for index := 0; index < prefix; index++ {
+		lines = append(lines, diffLine{kind: ' ', text: old[index], old: index + 1, new: index + 1})
+	}
+
+	i, j := 0, 0
+	for i < len(oldMiddle) || j < len(newMiddle) {
+		switch {
+		case i < len(oldMiddle) && j < len(newMiddle) && oldMiddle[i] == newMiddle[j]:
+			lines = append(lines, diffLine{kind: ' ', text: oldMiddle[i], old: prefix + i + 1, new: prefix + j + 1})
+			i++
+			j++
+		case j == len(newMiddle) || (i < len(oldMiddle) && lengths[i+1][j] >= lengths[i][j+1]):
+			lines = append(lines, diffLine{kind: '-', text: oldMiddle[i], old: prefix + i + 1})
+			i++
+		default:
+			lines = append(lines, diffLine{kind: '+', text: newMiddle[j], new: prefix + j + 1})
+			j++
+		}
+	}
+
+	for index := 0; index < suffix; index++ {
+		lines = append(lines, diffLine{
+			kind: ' ',
+			text: old[len(old)-suffix+index],
+			old:  len(old) - suffix + index + 1,
+			new:  len(current) - suffix + index + 1,
+		})
+	}
+
+	return lines
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,368 @@
+// Package migrate rewrites the errors.New and fmt.Errorf call sites into gopherpanic errors.
+//
+//	errors.New("user not found")             -> gopherpanic.New(gopherpanic.InternalError, "user not found")
+//	fmt.Errorf("user %d not found", id)      -> gopherpanic.Newf(gopherpanic.InternalError, "user %d not found", id)
+//	fmt.Errorf("read %s: %w", name, err)     -> gopherpanic.Newf(gopherpanic.IOError, "read %s: %w", name, err)
+//
+// The rewritten calls return a *gopherpanic.Error instead of an error. The calls returned as an error
+// result, given as argument or assigned to an existing variable or a variable typed error are replaced
+// as is, the calls declaring a new variable are converted into error so the variable keeps its type:
+//
+//	err := errors.New("start")               -> err := error(gopherpanic.New(gopherpanic.InternalError, "start"))
+//
+// The other call sites are left untouched and reported as skipped.
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+const (
+	gopherpanicPath = "github.com/ulphidius/gopherpanic"
+	gopherpanicName = "gopherpanic"
+)
+
+// Rewrite configuration
+type Options struct {
+	Code  string // Name of the gopherpanic Code used when no code is inferred (default InternalError)
+	Infer bool   // Infer the Code from the message keywords
+}
+
+// Result of a file rewrite
+type Result struct {
+	Source   []byte           // Rewritten source
+	Rewrites int              // Number of rewritten calls
+	Skipped  []token.Position // Calls which cannot be rewritten
+}
+
+type inferenceRule struct {
+	code    string
+	pattern *regexp.Regexp
+}
+
+// Keywords used to infer the code of a message, the first matching rule wins
+var inferenceRules = []inferenceRule{
+	{code: "TimeoutError", pattern: regexp.MustCompile(`\b(timeout|timed out|deadline)\b`)},
+	{code: "UnauthorizedError", pattern: regexp.MustCompile(`\b(unauthori[sz]ed|forbidden|permission|denied|credentials?)\b`)},
+	{code: "UnimplementedError", pattern: regexp.MustCompile(`\b(not implemented|unimplemented|unsupported)\b`)},
+	{code: "NetworkError", pattern: regexp.MustCompile(`\b(connect|connection|dial|network|http|request|response|dns)\b`)},
+	{code: "IOError", pattern: regexp.MustCompile(`\b(read|write|open|close|file|directory|disk)\b`)},
+	{code: "ClientError", pattern: regexp.MustCompile(`\b(invalid|missing|required|must|malformed|bad)\b`)},
+}
+
+// Rewrite the errors.New and fmt.Errorf calls of a Go source file
+func File(filename string, src []byte, options Options) (Result, error) {
+	if options.Code == "" {
+		options.Code = "InternalError"
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return Result{}, err
+	}
+
+	errorsName := importName(file, "errors")
+	fmtName := importName(file, "fmt")
+	result := Result{}
+	if errorsName == "" && fmtName == "" {
+		result.Source = src
+		return result, nil
+	}
+
+	// The calls use the name of the existing import, or a free name for the added import
+	name := importName(file, gopherpanicPath)
+	imported := name != ""
+	if !imported {
+		name = gopherpanicName
+		for index := 2; declares(file, name); index++ {
+			name = fmt.Sprintf("%s%d", gopherpanicName, index)
+		}
+	}
+	shadowed := imported && declares(file, name)
+
+	var functions []*ast.FuncType
+	astutil.Apply(file, func(cursor *astutil.Cursor) bool {
+		switch function := cursor.Node().(type) {
+		case *ast.FuncDecl:
+			functions = append(functions, function.Type)
+		case *ast.FuncLit:
+			functions = append(functions, function.Type)
+		}
+		return true
+	}, func(cursor *astutil.Cursor) bool {
+		var call *ast.CallExpr
+		switch node := cursor.Node().(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			functions = functions[:len(functions)-1]
+			return true
+		case *ast.CallExpr:
+			call = node
+		default:
+			return true
+		}
+
+		var replacement ast.Expr
+		switch {
+		case errorsName != "" && isPackageCall(call, errorsName, "New"):
+			replacement = rewriteNew(call, name, options)
+		case fmtName != "" && isPackageCall(call, fmtName, "Errorf"):
+			replacement = rewriteErrorf(call, name, options)
+		default:
+			return true
+		}
+
+		// The imported package is hidden by a declaration of the file
+		if shadowed {
+			replacement = nil
+		}
+
+		if replacement != nil {
+			replacement = convertForSite(cursor, functions, replacement)
+		}
+
+		if replacement == nil {
+			result.Skipped = append(result.Skipped, fset.Position(call.Pos()))
+			return true
+		}
+
+		cursor.Replace(replacement)
+		result.Rewrites++
+		return true
+	})
+
+	if result.Rewrites == 0 {
+		result.Source = src
+		return result, nil
+	}
+
+	if !imported && name == gopherpanicName {
+		astutil.AddImport(fset, file, gopherpanicPath)
+	} else if !imported {
+		astutil.AddNamedImport(fset, file, name, gopherpanicPath)
+	}
+	for path, name := range map[string]string{"errors": errorsName, "fmt": fmtName} {
+		if name != "" && !astutil.UsesImport(file, path) {
+			if name == path {
+				name = ""
+			}
+			astutil.DeleteNamedImport(fset, file, name, path)
+		}
+	}
+	ungroupSingleImport(file)
+
+	buffer := bytes.Buffer{}
+	if err := format.Node(&buffer, fset, file); err != nil {
+		return Result{}, err
+	}
+
+	result.Source = buffer.Bytes()
+	return result, nil
+}
+
+// Remove the parentheses of an import declaration which contains a single package
+func ungroupSingleImport(file *ast.File) {
+	for _, declaration := range file.Decls {
+		general, isGeneral := declaration.(*ast.GenDecl)
+		if !isGeneral || general.Tok != token.IMPORT {
+			continue
+		}
+
+		if len(general.Specs) == 1 && general.Lparen.IsValid() {
+			general.Lparen = token.NoPos
+			general.Rparen = token.NoPos
+		}
+	}
+}
+
+// errors.New(message) -> gopherpanic.New(code, message)
+func rewriteNew(call *ast.CallExpr, name string, options Options) ast.Expr {
+	if len(call.Args) != 1 {
+		return nil
+	}
+
+	return gopherpanicCall(name, "New", codeExpr(name, call.Args[0], options), call.Args[0])
+}
+
+// fmt.Errorf(format, args...) -> gopherpanic.Newf(code, format, args...)
+//
+// Newf handles the %w operands like fmt.Errorf, the wrapped errors stay reachable with errors.Is and errors.As.
+func rewriteErrorf(call *ast.CallExpr, name string, options Options) ast.Expr {
+	if len(call.Args) == 0 {
+		return nil
+	}
+
+	replacement := gopherpanicCall(name, "Newf", append([]ast.Expr{codeExpr(name, call.Args[0], options)}, call.Args...)...)
+	replacement.Ellipsis = call.Ellipsis
+	return replacement
+}
+
+// Adapt the *gopherpanic.Error replacement to its call site, nil if the site cannot receive it.
+//
+// The returned error results, the arguments and the assignments of existing variables accept the replacement,
+// the declarations of new variables convert it into error to keep the variable type.
+func convertForSite(cursor *astutil.Cursor, functions []*ast.FuncType, replacement ast.Expr) ast.Expr {
+	switch parent := cursor.Parent().(type) {
+	case *ast.ReturnStmt:
+		if len(functions) > 0 && isErrorType(resultType(functions[len(functions)-1], cursor.Index())) {
+			return replacement
+		}
+	case *ast.CallExpr:
+		if cursor.Name() == "Args" {
+			return replacement
+		}
+	case *ast.AssignStmt:
+		if len(parent.Lhs) != len(parent.Rhs) {
+			return nil
+		}
+
+		switch parent.Tok {
+		case token.ASSIGN:
+			return replacement
+		case token.DEFINE:
+			return convertIntoError(replacement)
+		}
+	case *ast.ValueSpec:
+		if cursor.Name() != "Values" || len(parent.Names) != len(parent.Values) {
+			return nil
+		}
+
+		if parent.Type == nil {
+			return convertIntoError(replacement)
+		}
+
+		if isErrorType(parent.Type) {
+			return replacement
+		}
+	}
+
+	return nil
+}
+
+func convertIntoError(expression ast.Expr) ast.Expr {
+	return &ast.CallExpr{Fun: ast.NewIdent("error"), Args: []ast.Expr{expression}}
+}
+
+// Type of the result at index, nil if there is none
+func resultType(function *ast.FuncType, index int) ast.Expr {
+	if function.Results == nil {
+		return nil
+	}
+
+	for _, field := range function.Results.List {
+		count := len(field.Names)
+		if count == 0 {
+			count = 1
+		}
+
+		if index < count {
+			return field.Type
+		}
+		index -= count
+	}
+
+	return nil
+}
+
+func isErrorType(expression ast.Expr) bool {
+	ident, isIdent := expression.(*ast.Ident)
+	return isIdent && ident.Name == "error" && ident.Obj == nil
+}
+
+func gopherpanicCall(name string, function string, args ...ast.Expr) *ast.CallExpr {
+	return &ast.CallExpr{
+		Fun:  &ast.SelectorExpr{X: ast.NewIdent(name), Sel: ast.NewIdent(function)},
+		Args: args,
+	}
+}
+
+func codeExpr(name string, message ast.Expr, options Options) ast.Expr {
+	code := options.Code
+	if options.Infer {
+		code = InferCode(literalText(message), options.Code)
+	}
+
+	return &ast.SelectorExpr{X: ast.NewIdent(name), Sel: ast.NewIdent(code)}
+}
+
+// Find the gopherpanic Code matching the message keywords
+func InferCode(message string, fallback string) string {
+	message = strings.ToLower(message)
+	for _, rule := range inferenceRules {
+		if rule.pattern.MatchString(message) {
+			return rule.code
+		}
+	}
+
+	return fallback
+}
+
+func literalText(expression ast.Expr) string {
+	literal, isLiteral := expression.(*ast.BasicLit)
+	if !isLiteral || literal.Kind != token.STRING {
+		return ""
+	}
+
+	text, err := strconv.Unquote(literal.Value)
+	if err != nil {
+		return ""
+	}
+
+	return text
+}
+
+func isPackageCall(call *ast.CallExpr, packageName string, function string) bool {
+	selector, isSelector := call.Fun.(*ast.SelectorExpr)
+	if !isSelector || selector.Sel.Name != function {
+		return false
+	}
+
+	ident, isIdent := selector.X.(*ast.Ident)
+	return isIdent && ident.Name == packageName && ident.Obj == nil
+}
+
+// The file declares an identifier with the name
+func declares(file *ast.File, name string) bool {
+	declared := file.Scope.Lookup(name) != nil
+	ast.Inspect(file, func(node ast.Node) bool {
+		ident, isIdent := node.(*ast.Ident)
+		if isIdent && ident.Name == name && ident.Obj != nil {
+			declared = true
+		}
+		return !declared
+	})
+
+	return declared
+}
+
+// Local name of an imported package (the last path element without explicit name), empty if the package is not imported
+func importName(file *ast.File, path string) string {
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || importPath != path {
+			continue
+		}
+
+		if spec.Name == nil {
+			return importPath[strings.LastIndex(importPath, "/")+1:]
+		}
+
+		if spec.Name.Name == "_" || spec.Name.Name == "." {
+			return ""
+		}
+
+		return spec.Name.Name
+	}
+
+	return ""
+}
@@ -0,0 +1,216 @@
+package migrate
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFile(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         string
+		options      Options
+		want         string
+		wantRewrites int
+		wantSkipped  int
+	}{
+		{
+			name: "OK - errors.New",
+			args: "package a\n\nimport \"errors\"\n\nvar ErrSample = errors.New(\"sample\")\n",
+			want: "package a\n\nimport \"github.com/ulphidius/gopherpanic\"\n\nvar ErrSample = error(gopherpanic.New(gopherpanic.InternalError, \"sample\"))\n",
+
+			wantRewrites: 1,
+		},
+		{
+			name:         "OK - fmt.Errorf without wrapping",
+			args:         "package a\n\nimport \"fmt\"\n\nfunc f(id int) error {\n\treturn fmt.Errorf(\"user %d is invalid\", id)\n}\n",
+			options:      Options{Infer: true},
+			want:         "package a\n\nimport \"github.com/ulphidius/gopherpanic\"\n\nfunc f(id int) error {\n\treturn gopherpanic.Newf(gopherpanic.ClientError, \"user %d is invalid\", id)\n}\n",
+			wantRewrites: 1,
+		},
+		{
+			name:         "OK - fmt.Errorf with wrapping",
+			args:         "package a\n\nimport \"fmt\"\n\nfunc f(err error) error {\n\treturn fmt.Errorf(\"cannot compute: %w\", err)\n}\n",
+			options:      Options{Code: "TimeoutError"},
+			want:         "package a\n\nimport \"github.com/ulphidius/gopherpanic\"\n\nfunc f(err error) error {\n\treturn gopherpanic.Newf(gopherpanic.TimeoutError, \"cannot compute: %w\", err)\n}\n",
+			wantRewrites: 1,
+		},
+		{
+			name:         "OK - aliased import",
+			args:         "package a\n\nimport stderrors \"errors\"\n\nvar ErrSample = stderrors.New(\"sample\")\n",
+			want:         "package a\n\nimport \"github.com/ulphidius/gopherpanic\"\n\nvar ErrSample = error(gopherpanic.New(gopherpanic.InternalError, \"sample\"))\n",
+			wantRewrites: 1,
+		},
+		{
+			name:         "OK - leading wrapping",
+			args:         "package a\n\nimport \"fmt\"\n\nfunc f(err error) error {\n\treturn fmt.Errorf(\"%w: cannot compute\", err)\n}\n",
+			want:         "package a\n\nimport \"github.com/ulphidius/gopherpanic\"\n\nfunc f(err error) error {\n\treturn gopherpanic.Newf(gopherpanic.InternalError, \"%w: cannot compute\", err)\n}\n",
+			wantRewrites: 1,
+		},
+		{
+			name:         "OK - non literal format",
+			args:         "package a\n\nimport \"fmt\"\n\nfunc f(format string, args ...any) error {\n\treturn fmt.Errorf(format, args...)\n}\n",
+			want:         "package a\n\nimport \"github.com/ulphidius/gopherpanic\"\n\nfunc f(format string, args ...any) error {\n\treturn gopherpanic.Newf(gopherpanic.InternalError, format, args...)\n}\n",
+			wantRewrites: 1,
+		},
+		{
+			name:         "OK - call sites",
+			args:         "package a\n\nimport \"errors\"\n\nfunc f(errs []error) error {\n\terr := errors.New(\"start\")\n\tvar typed error = errors.New(\"typed\")\n\terr = errors.New(\"assigned\")\n\terrs = append(errs, errors.New(\"argument\"))\n\treturn errors.Join(err, typed)\n}\n",
+			want:         "package a\n\nimport (\n\t\"errors\"\n\t\"github.com/ulphidius/gopherpanic\"\n)\n\nfunc f(errs []error) error {\n\terr := error(gopherpanic.New(gopherpanic.InternalError, \"start\"))\n\tvar typed error = gopherpanic.New(gopherpanic.InternalError, \"typed\")\n\terr = gopherpanic.New(gopherpanic.InternalError, \"assigned\")\n\terrs = append(errs, gopherpanic.New(gopherpanic.InternalError, \"argument\"))\n\treturn errors.Join(err, typed)\n}\n",
+			wantRewrites: 4,
+		},
+		{
+			name:        "KO - call sites which are not errors",
+			args:        "package a\n\nimport \"errors\"\n\nvar message = errors.New(\"sample\").Error()\n\nvar errs = []error{errors.New(\"sample\")}\n\nfunc f() any {\n\treturn errors.New(\"sample\")\n}\n",
+			want:        "package a\n\nimport \"errors\"\n\nvar message = errors.New(\"sample\").Error()\n\nvar errs = []error{errors.New(\"sample\")}\n\nfunc f() any {\n\treturn errors.New(\"sample\")\n}\n",
+			wantSkipped: 3,
+		},
+		{
+			name:         "OK - aliased gopherpanic import",
+			args:         "package a\n\nimport (\n\t\"errors\"\n\n\tgp \"github.com/ulphidius/gopherpanic\"\n)\n\nvar ErrSample = errors.New(\"sample\")\n\nvar ErrOther = gp.New(gp.IOError, \"other\")\n",
+			want:         "package a\n\nimport gp \"github.com/ulphidius/gopherpanic\"\n\nvar ErrSample = error(gp.New(gp.InternalError, \"sample\"))\n\nvar ErrOther = gp.New(gp.IOError, \"other\")\n",
+			wantRewrites: 1,
+		},
+		{
+			name:         "OK - gopherpanic identifier",
+			args:         "package a\n\nimport \"errors\"\n\nvar gopherpanic = \"mascot\"\n\nfunc f() error {\n\treturn errors.New(gopherpanic)\n}\n",
+			want:         "package a\n\nimport gopherpanic2 \"github.com/ulphidius/gopherpanic\"\n\nvar gopherpanic = \"mascot\"\n\nfunc f() error {\n\treturn gopherpanic2.New(gopherpanic2.InternalError, gopherpanic)\n}\n",
+			wantRewrites: 1,
+		},
+		{
+			name:        "KO - shadowed gopherpanic import",
+			args:        "package a\n\nimport (\n\t\"errors\"\n\n\t\"github.com/ulphidius/gopherpanic\"\n)\n\nvar ErrOther = gopherpanic.New(gopherpanic.IOError, \"other\")\n\nfunc f(gopherpanic string) error {\n\treturn errors.New(gopherpanic)\n}\n",
+			want:        "package a\n\nimport (\n\t\"errors\"\n\n\t\"github.com/ulphidius/gopherpanic\"\n)\n\nvar ErrOther = gopherpanic.New(gopherpanic.IOError, \"other\")\n\nfunc f(gopherpanic string) error {\n\treturn errors.New(gopherpanic)\n}\n",
+			wantSkipped: 1,
+		},
+		{
+			name: "OK - shadowed package",
+			args: "package a\n\nimport \"fmt\"\n\ntype printer struct{}\n\nfunc (printer) Errorf(string) error { return nil }\n\nfunc f() error {\n\tfmt := printer{}\n\treturn fmt.Errorf(\"sample\")\n}\n\nvar _ = fmt.Sprint\n",
+			want: "package a\n\nimport \"fmt\"\n\ntype printer struct{}\n\nfunc (printer) Errorf(string) error { return nil }\n\nfunc f() error {\n\tfmt := printer{}\n\treturn fmt.Errorf(\"sample\")\n}\n\nvar _ = fmt.Sprint\n",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := File("a.go", []byte(testCase.args), testCase.options)
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.want, string(result.Source))
+			assert.Equal(t, testCase.wantRewrites, result.Rewrites)
+			assert.Equal(t, testCase.wantSkipped, len(result.Skipped))
+		})
+	}
+}
+
+func TestFileBuilds(t *testing.T) {
+	goCommand, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+
+	library, err := filepath.Abs(filepath.Join("..", ".."))
+	assert.Nil(t, err)
+
+	source := `package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+var ErrNotFound = errors.New("not found")
+
+func Find(id int) error {
+	return fmt.Errorf("find %d: %w", id, ErrNotFound)
+}
+
+func Stat(name string) error {
+	err := errors.New("start")
+	if _, e := os.Stat(name); e != nil {
+		err = e
+	}
+	return err
+}
+
+func main() {
+	fmt.Println(errors.Is(Find(1), ErrNotFound), Stat("missing") != nil)
+}
+`
+	result, err := File("main.go", []byte(source), Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, result.Rewrites)
+
+	dir := t.TempDir()
+	mod := "module example.com/migrated\n\ngo 1.20\n\nrequire github.com/ulphidius/gopherpanic v0.0.0\n\nreplace github.com/ulphidius/gopherpanic => " + library + "\n"
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(mod), 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "main.go"), result.Source, 0o644))
+
+	for _, args := range [][]string{{"vet", "-mod=mod", "."}, {"run", "-mod=mod", "."}} {
+		command := exec.Command(goCommand, args...)
+		command.Dir = dir
+		output, err := command.CombinedOutput()
+		assert.Nil(t, err, string(output))
+		if args[0] == "run" {
+			assert.Equal(t, "true true\n", string(output))
+		}
+	}
+}
+
+func TestInferCode(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want string
+	}{
+		{name: "OK - timeout", args: "request timed out", want: "TimeoutError"},
+		{name: "OK - unauthorized", args: "Permission denied", want: "UnauthorizedError"},
+		{name: "OK - network", args: "cannot dial %s", want: "NetworkError"},
+		{name: "OK - io", args: "cannot open file", want: "IOError"},
+		{name: "OK - client", args: "missing parameter", want: "ClientError"},
+		{name: "OK - fallback", args: "cannot compute", want: "InternalError"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, InferCode(testCase.args, "InternalError"))
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		old     string
+		current string
+		want    string
+	}{
+		{
+			name:    "OK - identical",
+			old:     "a\nb\n",
+			current: "a\nb\n",
+			want:    "",
+		},
+		{
+			name:    "OK - separated hunks",
+			old:     "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			current: "1\nb\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n",
+			want:    "--- a.go\n+++ a.go\n@@ -1,5 +1,5 @@\n 1\n-2\n+b\n 3\n 4\n 5\n@@ -10,3 +10,4 @@\n 10\n 11\n 12\n+13\n",
+		},
+		{
+			name:    "OK - merged hunks",
+			old:     "1\n2\n3\n4\n5\n",
+			current: "0\n2\n3\n4\n6\n",
+			want:    "--- a.go\n+++ a.go\n@@ -1,5 +1,5 @@\n-1\n+0\n 2\n 3\n 4\n-5\n+6\n",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, Diff("a.go", []byte(testCase.old), []byte(testCase.current)))
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package gopherpanic
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type PathTrimming uint
+
+const (
+	TrimNone   PathTrimming = iota // Absolute path returned by runtime.Caller
+	TrimModule                     // Path relative to the module root, GOROOT, GOPATH, module cache or a registered prefix
+	TrimBase                       // File name only
+)
+
+// Trimming applied on the position files by every format and by JSON.
+//
+// Initialized from GOPHERPANIC_TRIM_PATHS (0: none, 1: module, 2: base). The Position keeps the absolute path.
+var GopherpanicPathTrimming PathTrimming = TrimNone
+
+var (
+	trimMutex    sync.RWMutex
+	trimPrefixes []string
+
+	moduleRoots sync.Map // directory -> module root ("" if the directory is outside of a module)
+
+	mainModulePath string // Module path of the main module, stripped from the files of a -trimpath build
+	trimpathBuild  bool
+)
+
+func init() {
+	trimming, err := strconv.Atoi(os.Getenv("GOPHERPANIC_TRIM_PATHS"))
+	if err == nil && trimming >= 0 && trimming <= 2 {
+		GopherpanicPathTrimming = PathTrimming(trimming)
+	}
+
+	if info, exists := debug.ReadBuildInfo(); exists {
+		mainModulePath = info.Main.Path
+		for _, setting := range info.Settings {
+			if setting.Key == "-trimpath" && setting.Value == "true" {
+				trimpathBuild = true
+			}
+		}
+	}
+}
+
+// Register prefixes removed from the position files by TrimModule (ex: "/home/runner/work/project/")
+func AddTrimPrefix(prefixes ...string) {
+	trimMutex.Lock()
+	defer trimMutex.Unlock()
+
+	trimPrefixes = append(trimPrefixes, prefixes...)
+}
+
+// File trimmed according to GopherpanicPathTrimming
+//
+// /home/runner/work/project/internal/user.go -> internal/user.go (TrimModule) or user.go (TrimBase)
+func (position Position) TrimmedFile() string {
+	return trimPath(position.File, GopherpanicPathTrimming)
+}
+
+// Convert into JSON with the file trimmed according to GopherpanicPathTrimming and the source link
+func (position Position) MarshalJSON() ([]byte, error) {
+	type plain Position
+	url := position.SourceURL()
+	position.File = position.TrimmedFile()
+
+	return json.Marshal(struct {
+		plain
+		URL string `json:"url,omitempty"`
+	}{plain: plain(position), URL: url})
+}
+
+func trimPath(file string, trimming PathTrimming) string {
+	if file == "" {
+		return file
+	}
+
+	switch trimming {
+	case TrimBase:
+		return filepath.Base(file)
+	case TrimModule:
+		return trimModulePath(file)
+	default:
+		return file
+	}
+}
+
+func trimModulePath(file string) string {
+	if relative, found := cutTrimPrefix(file); found {
+		return relative
+	}
+
+	// -trimpath builds already replace the directories by module paths (github.com/user/project/file.go)
+	if !filepath.IsAbs(file) {
+		if trimpathBuild && mainModulePath != "" && strings.HasPrefix(file, mainModulePath+"/") {
+			return strings.TrimPrefix(file, mainModulePath+"/")
+		}
+		return file
+	}
+
+	for _, root := range toolchainRoots() {
+		if relative, found := strings.CutPrefix(file, root); found {
+			return relative
+		}
+	}
+
+	if root := moduleRoot(filepath.Dir(file)); root != "" {
+		if relative, err := filepath.Rel(root, file); err == nil {
+			return filepath.ToSlash(relative)
+		}
+	}
+
+	for _, directory := range gopaths() {
+		if relative, found := strings.CutPrefix(file, filepath.ToSlash(filepath.Join(directory, "src"))+"/"); found {
+			return relative
+		}
+	}
+
+	return file
+}
+
+func cutTrimPrefix(file string) (string, bool) {
+	trimMutex.RLock()
+	defer trimMutex.RUnlock()
+
+	for _, prefix := range trimPrefixes {
+		if relative, found := strings.CutPrefix(file, prefix); found && prefix != "" {
+			return strings.TrimLeft(relative, "/"), true
+		}
+	}
+
+	return "", false
+}
+
+// Directories whose content is identified by its import path: GOROOT and module cache
+func toolchainRoots() []string {
+	var roots []string
+	if goroot := runtime.GOROOT(); goroot != "" {
+		roots = append(roots, filepath.ToSlash(filepath.Join(goroot, "src"))+"/")
+	}
+
+	if modcache := os.Getenv("GOMODCACHE"); modcache != "" {
+		roots = append(roots, filepath.ToSlash(modcache)+"/")
+	}
+
+	for _, directory := range gopaths() {
+		roots = append(roots, filepath.ToSlash(filepath.Join(directory, "pkg", "mod"))+"/")
+	}
+
+	return roots
+}
+
+func gopaths() []string {
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			gopath = filepath.Join(home, "go")
+		}
+	}
+
+	var directories []string
+	for _, directory := range filepath.SplitList(gopath) {
+		if directory != "" {
+			directories = append(directories, directory)
+		}
+	}
+
+	return directories
+}
+
+// Nearest parent directory which contains a go.mod file
+func moduleRoot(directory string) string {
+	if root, exists := moduleRoots.Load(directory); exists {
+		return root.(string)
+	}
+
+	root := ""
+	if _, err := os.Stat(filepath.Join(directory, "go.mod")); err == nil {
+		root = directory
+	} else if parent := filepath.Dir(directory); parent != directory {
+		root = moduleRoot(parent)
+	}
+
+	moduleRoots.Store(directory, root)
+	return root
+}
@@ -0,0 +1,100 @@
+package gopherpanic
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrimPath(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "project", "internal", "user"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "project", "go.mod"), []byte("module example.com/project\n"), 0o644))
+	t.Setenv("GOMODCACHE", filepath.Join(root, "modcache"))
+
+	tests := []struct {
+		name   string
+		fields PathTrimming
+		args   string
+		want   string
+	}{
+		{
+			name:   "OK - none",
+			fields: TrimNone,
+			args:   filepath.Join(root, "project", "internal", "user", "user.go"),
+			want:   filepath.Join(root, "project", "internal", "user", "user.go"),
+		},
+		{
+			name:   "OK - base",
+			fields: TrimBase,
+			args:   filepath.Join(root, "project", "internal", "user", "user.go"),
+			want:   "user.go",
+		},
+		{
+			name:   "OK - module root",
+			fields: TrimModule,
+			args:   filepath.Join(root, "project", "internal", "user", "user.go"),
+			want:   "internal/user/user.go",
+		},
+		{
+			name:   "OK - GOROOT",
+			fields: TrimModule,
+			args:   filepath.Join(runtime.GOROOT(), "src", "fmt", "print.go"),
+			want:   "fmt/print.go",
+		},
+		{
+			name:   "OK - module cache",
+			fields: TrimModule,
+			args:   filepath.Join(root, "modcache", "github.com", "stretchr", "testify@v1.8.2", "assert", "assertions.go"),
+			want:   "github.com/stretchr/testify@v1.8.2/assert/assertions.go",
+		},
+		{
+			name:   "OK - outside of a module",
+			fields: TrimModule,
+			args:   filepath.Join(root, "script.go"),
+			want:   filepath.Join(root, "script.go"),
+		},
+		{
+			name:   "OK - already trimmed",
+			fields: TrimModule,
+			args:   "github.com/stretchr/testify@v1.8.2/assert/assertions.go",
+			want:   "github.com/stretchr/testify@v1.8.2/assert/assertions.go",
+		},
+		{
+			name:   "OK - empty",
+			fields: TrimBase,
+			args:   "",
+			want:   "",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, trimPath(testCase.args, testCase.fields))
+		})
+	}
+}
+
+func TestAddTrimPrefix(t *testing.T) {
+	defer func() { trimPrefixes = nil }()
+
+	AddTrimPrefix("/home/runner/work/project")
+	assert.Equal(t, "cmd/main.go", trimPath("/home/runner/work/project/cmd/main.go", TrimModule))
+	assert.Equal(t, "/home/runner/work/project/cmd/main.go", trimPath("/home/runner/work/project/cmd/main.go", TrimNone))
+}
+
+func TestPositionTrimmedFormats(t *testing.T) {
+	trimming := GopherpanicPathTrimming
+	GopherpanicPathTrimming = TrimModule
+	defer func() { GopherpanicPathTrimming = trimming }()
+
+	err := New(IOError, "disk full") // Error check based on the current line
+	err.Traces = []Trace{{Message: "write failed", Position: err.Position}}
+
+	assert.Equal(t, "trim_test.go:94: Error: 1:failed to perform IO task:disk full\ntrim_test.go:94: Error: write failed", err.FormatWithTraces(false))
+	assert.Contains(t, err.FormatJSON(false), `"position":{"file":"trim_test.go","line":94,"function":"github.com/ulphidius/gopherpanic.TestPositionTrimmedFormats"`)
+	assert.True(t, filepath.IsAbs(err.Position.File))
+}
@@ -0,0 +1,290 @@
+package gopherpanic
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Invalid field of a client input
+type Violation struct {
+	Field      string `json:"field"`           // JSON pointer (/address/city) or dotted path (address.city)
+	Constraint string `json:"constraint"`      // Name of the failed constraint (required, min, format, ...)
+	Value      any    `json:"value,omitempty"` // Rejected value, redacted when rendered (use Sensitive to hide it)
+	Message    string `json:"message"`         // Human readable explanation
+}
+
+// List of field violations carried by a validation Error
+type Violations []Violation
+
+// Problem details extension member describing an invalid parameter (RFC 7807 invalid-params)
+type InvalidParam struct {
+	Name   string `json:"name"`   // Field of the violation
+	Reason string `json:"reason"` // Message of the violation
+}
+
+// Fluent collector of field violations.
+//
+// The zero value is an empty collection.
+type Validation struct {
+	violations Violations
+}
+
+// Create an empty Validation
+func Validate() *Validation {
+	return &Validation{}
+}
+
+// Record a violation of the field
+func (validation *Validation) Add(field string, constraint string, value any, message string) *Validation {
+	validation.violations = append(validation.violations, Violation{
+		Field:      field,
+		Constraint: constraint,
+		Value:      value,
+		Message:    message,
+	})
+	return validation
+}
+
+// Record a violation of the field if the condition is false
+func (validation *Validation) Check(valid bool, field string, constraint string, value any, message string) *Validation {
+	if valid {
+		return validation
+	}
+
+	return validation.Add(field, constraint, value, message)
+}
+
+// Record a violation of the field if the value is its zero value
+func (validation *Validation) Required(field string, value any) *Validation {
+	valid := value != nil && !reflect.ValueOf(value).IsZero()
+	return validation.Check(valid, field, "required", nil, field+" is required")
+}
+
+// Record the violations of a nested structure, their fields are prefixed by the field of the structure.
+//
+// The prefix style (JSON pointer or dotted path) is chosen from the field.
+func (validation *Validation) Nested(field string, nested *Validation) *Validation {
+	for _, violation := range nested.violations {
+		violation.Field = joinFieldPath(field, violation.Field)
+		validation.violations = append(validation.violations, violation)
+	}
+
+	return validation
+}
+
+// Recorded violations
+func (validation Validation) Violations() Violations {
+	return validation.violations
+}
+
+// Number of recorded violations
+func (validation Validation) Len() int {
+	return len(validation.violations)
+}
+
+// The violations as a ClientError spawned at the current position, nil if there is none
+func (validation *Validation) ErrorOrNil() error {
+	if validation == nil || len(validation.violations) == 0 {
+		return nil
+	}
+
+	err := newValidation(validation.violations)
+	err.Position = Position{}.spawn(2)
+	return err
+}
+
+// Create a ClientError holding the violations with the current spawn position.
+//
+// The message and the public message count the invalid fields.
+func NewValidation(violations ...Violation) *Error {
+	err := newValidation(violations)
+	err.Position = Position{}.spawn(2)
+	return err
+}
+
+func newValidation(violations Violations) *Error {
+	message := fmt.Sprintf("%d invalid fields", len(violations))
+	if len(violations) == 1 {
+		message = "1 invalid field"
+	}
+
+	return &Error{
+		Code:          ClientError,
+		Message:       message,
+		PublicMessage: message,
+		Violations:    violations,
+		InstanceID:    newInstanceID(),
+		Time:          newTimestamp(),
+		Environment:   newEnvironment(),
+	}
+}
+
+// Build a JSON pointer (RFC 6901) from path segments
+//
+// JSONPointer("items", 0, "a/b") -> /items/0/a~1b
+func JSONPointer(segments ...any) string {
+	var builder strings.Builder
+	for _, segment := range segments {
+		builder.WriteString("/")
+		builder.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(fmt.Sprint(segment)))
+	}
+
+	return builder.String()
+}
+
+// Build a dotted path from path segments, the integer segments are rendered as indexes
+//
+// DottedPath("items", 0, "name") -> items[0].name
+func DottedPath(segments ...any) string {
+	var builder strings.Builder
+	for _, segment := range segments {
+		if index, isIndex := segment.(int); isIndex {
+			builder.WriteString("[" + strconv.Itoa(index) + "]")
+			continue
+		}
+
+		if builder.Len() > 0 {
+			builder.WriteString(".")
+		}
+		builder.WriteString(fmt.Sprint(segment))
+	}
+
+	return builder.String()
+}
+
+func joinFieldPath(prefix string, field string) string {
+	switch {
+	case prefix == "":
+		return field
+	case field == "":
+		return prefix
+	case strings.HasPrefix(prefix, "/"):
+		return prefix + "/" + strings.TrimPrefix(field, "/")
+	case strings.HasPrefix(field, "["):
+		return prefix + field
+	default:
+		return prefix + "." + field
+	}
+}
+
+// Copy of the violation with the DefaultRedactor applied on the message and the rejected value
+func (violation Violation) Redacted() Violation {
+	violation.Message = redact(violation.Message)
+
+	switch value := violation.Value.(type) {
+	case string:
+		violation.Value = redact(value)
+	case error:
+		violation.Value = redact(value.Error())
+	case fmt.Stringer:
+		violation.Value = redact(value.String())
+	case nil:
+	default:
+		violation.Value = redactValue(value)
+	}
+
+	return violation
+}
+
+// The booleans and numbers keep their JSON type unless their text is redacted, the other values
+// (complex numbers, non-finite floats, slices, maps, structs, ...) are replaced by their redacted text
+func redactValue(value any) any {
+	text := fmt.Sprint(value)
+	reflected := reflect.ValueOf(value)
+
+	switch reflected.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if redact(text) == text {
+			return value
+		}
+	case reflect.Float32, reflect.Float64:
+		if float := reflected.Float(); redact(text) == text && !math.IsNaN(float) && !math.IsInf(float, 0) {
+			return value
+		}
+	}
+
+	return redact(text)
+}
+
+// Convert into string
+//
+// age: age must be positive [min, rejected: -1]
+func (violation Violation) Format() string {
+	redacted := violation.Redacted()
+	result := fmt.Sprintf("%s: %s [%s", escapeText(redacted.Field), textMessage(redacted.Message), escapeText(redacted.Constraint))
+	if redacted.Value != nil {
+		result += ", rejected: " + textMessage(fmt.Sprint(redacted.Value))
+	}
+
+	return result + "]"
+}
+
+// Copy of the violations with the DefaultRedactor applied
+func (violations Violations) Redacted() Violations {
+	if violations == nil {
+		return nil
+	}
+
+	redacted := make(Violations, len(violations))
+	for index, violation := range violations {
+		redacted[index] = violation.Redacted()
+	}
+
+	return redacted
+}
+
+// Convert into an indented list, one violation per line
+//
+//	name: name is required [required]
+//	age: age must be positive [min, rejected: -1]
+func (violations Violations) Format() string {
+	lines := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		lines = append(lines, "\t"+violation.Format())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Violations rendered after the message of an Error
+func (violations Violations) format(custom bool) string {
+	indent := "\n\t"
+	if custom {
+		indent = "\n\t\t"
+	}
+
+	var builder strings.Builder
+	for _, violation := range violations {
+		builder.WriteString(indent + violation.Format())
+	}
+
+	return builder.String()
+}
+
+// Convert into the invalid-params member of the problem details
+func (violations Violations) InvalidParams() []InvalidParam {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	params := make([]InvalidParam, 0, len(violations))
+	for _, violation := range violations {
+		params = append(params, InvalidParam{Name: violation.Field, Reason: redact(violation.Message)})
+	}
+
+	return params
+}
+
+// Convert into a redacted JSON array string (with or without indentation)
+func (violations Violations) FormatJSON(indent bool) string {
+	violations = violations.Redacted()
+	if violations == nil {
+		violations = Violations{}
+	}
+
+	return formatJSON(violations, indent)
+}
@@ -0,0 +1,209 @@
+package gopherpanic
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validationSample() Error {
+	return Error{
+		Code:          ClientError,
+		Message:       "2 invalid fields",
+		PublicMessage: "2 invalid fields",
+		Position:      Position{File: "user.go", Line: 12},
+		Violations: Violations{
+			{Field: "name", Constraint: "required", Message: "name is required"},
+			{Field: "email", Constraint: "format", Value: "john@example", Message: "email must be valid"},
+		},
+	}
+}
+
+func TestValidation(t *testing.T) {
+	address := Validate().
+		Required("city", "").
+		Check(false, "zip", "pattern", "75OO1", "zip must contain 5 digits")
+
+	validation := Validate().
+		Required("name", "John").
+		Required("age", 0).
+		Add("email", "format", "john@", "email must be valid").
+		Nested("address", address)
+
+	assert.Equal(t, 4, validation.Len())
+	assert.Equal(t, Violations{
+		{Field: "age", Constraint: "required", Message: "age is required"},
+		{Field: "email", Constraint: "format", Value: "john@", Message: "email must be valid"},
+		{Field: "address.city", Constraint: "required", Message: "city is required"},
+		{Field: "address.zip", Constraint: "pattern", Value: "75OO1", Message: "zip must contain 5 digits"},
+	}, validation.Violations())
+
+	err := validation.ErrorOrNil().(*Error)
+	assert.Equal(t, ClientError, err.Code)
+	assert.Equal(t, "4 invalid fields", err.Message)
+	assert.Equal(t, validation.Violations(), err.Violations)
+
+	assert.Nil(t, Validate().Required("name", "John").ErrorOrNil())
+}
+
+func TestNewValidation(t *testing.T) {
+	err := NewValidation(Violation{Field: "/name", Constraint: "required", Message: "name is required"})
+
+	assert.Equal(t, "1 invalid field", err.Message)
+	assert.Equal(t, "1 invalid field", err.PublicMessage)
+	assert.Equal(t, "validation_test.go", filepath.Base(err.Position.File))
+	assert.Equal(t, 52, err.Position.Line)
+}
+
+func TestFieldPath(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{
+			name: "OK - JSON pointer",
+			got:  JSONPointer("items", 0, "a/b", "c~d"),
+			want: "/items/0/a~1b/c~0d",
+		},
+		{
+			name: "OK - dotted path",
+			got:  DottedPath("items", 0, "name"),
+			want: "items[0].name",
+		},
+		{
+			name: "OK - nested JSON pointer",
+			got:  Validate().Nested("/address", Validate().Required("/city", nil)).Violations()[0].Field,
+			want: "/address/city",
+		},
+		{
+			name: "OK - nested index",
+			got:  Validate().Nested("items", Validate().Required("[0]", nil)).Violations()[0].Field,
+			want: "items[0]",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, testCase.got)
+		})
+	}
+}
+
+func TestValidationFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		format func() string
+		want   string
+	}{
+		{
+			name:   "OK - GNU",
+			format: func() string { return validationSample().Format(false, true) },
+			want: "user.go:12: Error: 4:failed to perform client api task:2 invalid fields\n" +
+				"\tname: name is required [required]\n" +
+				"\temail: email must be valid [format, rejected: john@example]",
+		},
+		{
+			name:   "OK - custom",
+			format: func() string { return validationSample().Format(true, false) },
+			want: "code id: 4; description: failed to perform client api task\n\terror message: 2 invalid fields\n" +
+				"\t\tname: name is required [required]\n" +
+				"\t\temail: email must be valid [format, rejected: john@example]",
+		},
+		{
+			name:   "OK - violations",
+			format: func() string { return validationSample().Violations.Format() },
+			want:   "\tname: name is required [required]\n\temail: email must be valid [format, rejected: john@example]",
+		},
+		{
+			name: "OK - redacted value",
+			format: func() string {
+				return Violation{Field: "password", Constraint: "min", Value: Sensitive("abc"), Message: "password is too short"}.Format()
+			},
+			want: "password: password is too short [min, rejected: [REDACTED]]",
+		},
+		{
+			name: "OK - JSON array",
+			format: func() string {
+				return Violations{{Field: "contact", Constraint: "format", Value: "john@example.com", Message: "contact is invalid"}}.FormatJSON(false)
+			},
+			want: `[{"field":"contact","constraint":"format","value":"[REDACTED]","message":"contact is invalid"}]`,
+		},
+		{
+			name: "OK - redacted number",
+			format: func() string {
+				return Violations{{Field: "card", Constraint: "luhn", Value: int64(4111111111111111), Message: "card is invalid"}}.FormatJSON(false)
+			},
+			want: `[{"field":"card","constraint":"luhn","value":"[REDACTED]","message":"card is invalid"}]`,
+		},
+		{
+			name: "OK - redacted number text",
+			format: func() string {
+				return Violations{{Field: "card", Constraint: "luhn", Value: int64(4111111111111111), Message: "card is invalid"}}.Format()
+			},
+			want: "\tcard: card is invalid [luhn, rejected: [REDACTED]]",
+		},
+		{
+			name: "OK - number kept",
+			format: func() string {
+				return Violations{{Field: "age", Constraint: "min", Value: -1, Message: "age must be positive"}}.FormatJSON(false)
+			},
+			want: `[{"field":"age","constraint":"min","value":-1,"message":"age must be positive"}]`,
+		},
+		{
+			name: "OK - complex number",
+			format: func() string {
+				return Violations{{Field: "ratio", Constraint: "real", Value: complex(1, 2), Message: "ratio must be real"}}.FormatJSON(false)
+			},
+			want: `[{"field":"ratio","constraint":"real","value":"(1+2i)","message":"ratio must be real"}]`,
+		},
+		{
+			name: "OK - non-finite float",
+			format: func() string {
+				return Violations{{Field: "ratio", Constraint: "finite", Value: math.Inf(1), Message: "ratio must be finite"}}.FormatJSON(false)
+			},
+			want: `[{"field":"ratio","constraint":"finite","value":"+Inf","message":"ratio must be finite"}]`,
+		},
+		{
+			name: "OK - redacted composite value",
+			format: func() string {
+				return Violations{{Field: "contacts", Constraint: "unique", Value: []string{"john@example.com", "john@example.com"}, Message: "contacts must be unique"}}.FormatJSON(false)
+			},
+			want: `[{"field":"contacts","constraint":"unique","value":"[[REDACTED] [REDACTED]]","message":"contacts must be unique"}]`,
+		},
+		{
+			name:   "KO - marshal error",
+			format: func() string { return Error{Code: ClientError, Args: Args{"ratio": complex(1, 2)}}.FormatJSON(false) },
+			want:   `{"format_error":"json: error calling MarshalJSON for type *gopherpanic.Error: json: unsupported type: complex128"}`,
+		},
+		{
+			name:   "OK - empty JSON array",
+			format: func() string { return Violations(nil).FormatJSON(false) },
+			want:   `[]`,
+		},
+		{
+			name:   "OK - public",
+			format: func() string { return validationSample().Public().FormatJSON(false) },
+			want:   `{"type":"about:blank","title":"failed to perform client api task","status":400,"detail":"2 invalid fields","code":4,"invalid-params":[{"name":"name","reason":"name is required"},{"name":"email","reason":"email must be valid"}]}`,
+		},
+		{
+			name:   "OK - public text",
+			format: func() string { return validationSample().Public().Format() },
+			want: "Error: 4:failed to perform client api task:2 invalid fields\n" +
+				"\tname: name is required\n" +
+				"\temail: email must be valid",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, testCase.format())
+		})
+	}
+}
+
+func TestValidationFormatJSONComplex(t *testing.T) {
+	assert.Contains(t, NewValidation(Violation{Field: "ratio", Value: complex(1, 2)}).FormatJSON(false), `"value":"(1+2i)"`)
+}